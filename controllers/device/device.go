@@ -4,9 +4,13 @@ import (
 	"context"
 	"sync"
 
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 	"github.com/timoth-y/chainmetric-core/models"
 	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/controllers/storage"
 	"github.com/timoth-y/chainmetric-iot/model"
+	"github.com/timoth-y/chainmetric-iot/shared"
 )
 
 // Device defines driver for the IoT device itself.
@@ -19,9 +23,10 @@ type Device struct {
 
 	cacheLayer
 
-	sensors       sensor.SensorsRegister
-	staticSensors sensor.SensorsRegister
+	sensors       *sensor.Registry
+	staticSensors *sensor.Registry
 
+	paused       bool
 	active       bool
 	cancelDevice context.CancelFunc
 }
@@ -33,13 +38,31 @@ func New(modules ...Module) *Device {
 	dev := &Device{
 		ctx:           ctx,
 		cacheLayer:    newCacheLayer(),
-		sensors:       make(sensor.SensorsRegister),
-		staticSensors: make(sensor.SensorsRegister),
+		sensors:       sensor.NewRegistry(viper.GetDuration("engine.sensor_sleep_standby_timeout")),
+		staticSensors: sensor.NewRegistry(0),
 		cancelDevice:  cancel,
 	}
 
 	dev.modulesReg = modules
 
+	// Sensing remains paused after reboot, until it is explicitly resumed:
+	if paused, err := storage.LoadPausedState(); err != nil {
+		shared.Logger.Error(errors.Wrap(err, "failed to load persisted paused state"))
+	} else {
+		dev.paused = paused
+	}
+
+	// Supported metrics are kept in sync with sensors register, regardless of which component has changed it:
+	dev.sensors.Subscribe(func(event sensor.RegistryEvent) {
+		if len(event.Added) == 0 && len(event.Removed) == 0 {
+			return
+		}
+
+		if dev.IsLoggedToNetwork() {
+			dev.updateSupportedMetrics()
+		}
+	})
+
 	return dev
 }
 
@@ -152,8 +152,10 @@ func (c *cacheLayer) PutRequirementsToCache(reqs ...*models.Requirements) (reque
 
 	for _, req := range reqs {
 		request := &model.SensorsReadingRequest{
+			ID:      req.ID,
 			AssetID: req.AssetID,
 			Metrics: req.Metrics.Metrics(),
+			Limits:  req.Metrics,
 			Period:  time.Second * time.Duration(req.Period),
 		}
 
@@ -4,6 +4,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/timoth-y/chainmetric-core/models"
 	"github.com/timoth-y/chainmetric-core/models/requests"
+	"github.com/timoth-y/chainmetric-iot/controllers/storage"
 	"github.com/timoth-y/chainmetric-iot/model"
 	"github.com/timoth-y/chainmetric-iot/network/blockchain"
 	"github.com/timoth-y/chainmetric-iot/shared"
@@ -49,6 +50,41 @@ func (d *Device) SetState(state models.DeviceState) error {
 	return nil
 }
 
+// Paused determines whether the Device sensing is paused.
+func (d *Device) Paused() bool {
+	d.stateMutex.Lock()
+	defer d.stateMutex.Unlock()
+
+	return d.paused
+}
+
+// SetPaused pauses or resumes the Device sensing by updating its state in blockchain network,
+// and persists it locally, so that the Device would stay paused after reboot.
+func (d *Device) SetPaused(paused bool) error {
+	if d.Paused() == paused {
+		return errors.Errorf("conflict setting state: device is already '%s'", operatingState(paused))
+	}
+
+	if err := d.SetState(operatingState(paused)); err != nil {
+		return err
+	}
+
+	if err := storage.SavePausedState(paused); err != nil {
+		shared.Logger.Warning(errors.Wrap(err, "failed to persist paused state"))
+	}
+
+	d.stateMutex.Lock()
+	d.paused = paused
+	d.stateMutex.Unlock()
+
+	return nil
+}
+
+// OperatingState returns state the Device has while being on network, which is either online or paused.
+func (d *Device) OperatingState() models.DeviceState {
+	return operatingState(d.Paused())
+}
+
 // Location returns Device current location.
 func (d *Device) Location() models.Location {
 	return d.state.Location
@@ -110,15 +146,19 @@ func (d *Device) SetSpecs(setter func(specs *model.DeviceSpecs)) error {
 
 	d.specs = *specs
 
-	req := requests.DeviceUpdateRequest{
-		Hostname: &specs.Hostname,
-		IP: &specs.IPAddress,
-		Supports: specs.Supports,
-		State: &specs.State,
+	req := model.DeviceSpecsUpdateRequest{
+		DeviceUpdateRequest: requests.DeviceUpdateRequest{
+			Hostname: &specs.Hostname,
+			IP: &specs.IPAddress,
+			Supports: specs.Supports,
+			State: &specs.State,
+		},
+		Capabilities: specs.Capabilities,
+		Quarantined: &specs.Quarantined,
 	}
 
 	if d.IsLoggedToNetwork() {
-		if err := blockchain.Contracts.Devices.Update(d.ID(), req); err != nil {
+		if err := blockchain.Contracts.Devices.UpdateSpecs(d.ID(), req); err != nil {
 			return errors.Wrap(err,"failed to update device specs")
 		}
 	} else {
@@ -155,3 +195,11 @@ func (d *Device) SetBattery(battery models.DeviceBattery) error {
 
 	return nil
 }
+
+func operatingState(paused bool) models.DeviceState {
+	if paused {
+		return models.DevicePaused
+	}
+
+	return models.DeviceOnline
+}
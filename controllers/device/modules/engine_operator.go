@@ -2,14 +2,19 @@ package modules
 
 import (
 	"context"
+	"os"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 	"github.com/timoth-y/chainmetric-core/models"
 	"github.com/timoth-y/chainmetric-core/utils"
 	"github.com/timoth-y/chainmetric-iot/controllers/device"
 	"github.com/timoth-y/chainmetric-iot/controllers/engine"
+	"github.com/timoth-y/chainmetric-iot/controllers/storage"
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
 	"github.com/timoth-y/chainmetric-iot/model"
+	"github.com/timoth-y/chainmetric-iot/model/config"
 	"github.com/timoth-y/chainmetric-iot/model/events"
 	"github.com/timoth-y/chainmetric-iot/network/blockchain"
 	"github.com/timoth-y/chainmetric-iot/shared"
@@ -19,7 +24,8 @@ import (
 // EngineOperator implements device.Module for engine.SensorsReader operating.
 type EngineOperator struct {
 	moduleBase
-	engine *engine.SensorsReader
+	engine   *engine.SensorsReader
+	recorder *sensor.Recorder
 }
 
 // WithEngineOperator can be used to setup EngineOperator logical device.Module onto the device.Device.
@@ -30,6 +36,28 @@ func WithEngineOperator() device.Module {
 	}
 }
 
+func (m *EngineOperator) Setup(device *device.Device) error {
+	if err := m.moduleBase.Setup(device); err != nil {
+		return err
+	}
+
+	// Engine shares sensors registry with the device, so that both are always in sync with each other:
+	m.engine.SetRegistry(device.SensorsRegistry())
+
+	m.setupCalibration()
+	m.setupRecording()
+
+	return nil
+}
+
+func (m *EngineOperator) Close() error {
+	if m.recorder != nil {
+		shared.Execute(m.recorder.Close, "failed to close readings recording")
+	}
+
+	return m.moduleBase.Close()
+}
+
 func (m *EngineOperator) Start(ctx context.Context) {
 	go m.Do(func() {
 		if !m.trySyncWithDeviceLifecycle(ctx, m.Start) {
@@ -55,10 +83,7 @@ func (m *EngineOperator) Start(ctx context.Context) {
 
 		// Listen and changes in device's sensors register:
 		eventdriver.SubscribeHandler(events.SensorsRegisterChanged, func(_ context.Context, v interface{}) error {
-			if payload, ok := v.(events.SensorsRegisterChangedPayload); ok {
-				m.engine.RegisterSensors(payload.Added...)
-				m.engine.UnregisterSensors(payload.Removed...)
-
+			if _, ok := v.(events.SensorsRegisterChangedPayload); ok {
 				// If engine wasn't started yet it is because there weren't any available sensors before.
 				// If there is ones now, engine could start processing requests.
 				if !m.engine.Active() && m.RegisteredSensors().NotEmpty() {
@@ -72,6 +97,21 @@ func (m *EngineOperator) Start(ctx context.Context) {
 			return eventdriver.ErrIncorrectPayload
 		})
 
+		// Listen on sensing being paused to stop handling requests without dropping them from cache:
+		eventdriver.SubscribeHandler(events.DeviceSensingPaused, func(_ context.Context, _ interface{}) error {
+			m.pause()
+			return nil
+		})
+
+		// Listen on sensing being resumed to restore handling of the cached requests:
+		eventdriver.SubscribeHandler(events.DeviceSensingResumed, func(_ context.Context, _ interface{}) error {
+			if m.engine.Active() {
+				m.actOnCachedRequests(ctx)
+			}
+
+			return nil
+		})
+
 		// Listen and changes in parameters cache:
 		eventdriver.SubscribeHandler(events.CacheChanged, func(_ context.Context, _ interface{}) error {
 			m.actOnCachedRequests(ctx)
@@ -79,34 +119,229 @@ func (m *EngineOperator) Start(ctx context.Context) {
 		})
 
 		if m.waitUntilSensorsDetected() {
-			m.engine.RegisterSensors(m.RegisteredSensors().ToList()...)
 			m.engine.Run(ctx)
 		}
 	})
 }
 
 
+// setupCalibration provides engine with per-sensor calibration from the local store,
+// importing the calibration table file beforehand, if such is configured.
+func (m *EngineOperator) setupCalibration() {
+	var (
+		path = viper.GetString("engine.calibration.file")
+	)
+
+	store, err := storage.NewCalibrationStore()
+	if err != nil {
+		shared.Logger.Warning(errors.Wrap(err, "sensors readings calibration is disabled"))
+		return
+	}
+
+	if len(path) != 0 {
+		if calibrations, err := storage.LoadCalibrationsFile(path); os.IsNotExist(err) {
+			shared.Logger.Debugf("Calibration table '%s' not found, import is skipped", path)
+		} else if err != nil {
+			shared.Logger.Error(errors.Wrapf(err, "failed to load calibration table from '%s'", path))
+		} else if imported, err := store.Import(calibrations...); err != nil {
+			shared.Logger.Error(errors.Wrapf(err, "failed to import calibration table from '%s'", path))
+		} else if imported != 0 {
+			shared.Logger.Infof("Imported %d sensor calibration(s) from '%s'", imported, path)
+		}
+	}
+
+	m.engine.SetCalibrations(store)
+}
+
+// setupRecording enables recording of all harvested readings into the file, if such is configured.
+func (m *EngineOperator) setupRecording() {
+	var (
+		path = viper.GetString("engine.recording.file")
+		err error
+	)
+
+	if len(path) == 0 {
+		return
+	}
+
+	if m.recorder, err = sensor.NewRecorder(path); err != nil {
+		shared.Logger.Error(errors.Wrapf(err, "failed to start readings recording to '%s'", path))
+		return
+	}
+
+	shared.Logger.Infof("Harvested readings are being recorded to '%s'", path)
+	m.engine.SetRecorder(m.recorder)
+}
+
 func (m *EngineOperator) actOnRequest(ctx context.Context, request *model.SensorsReadingRequest) {
-	if request.IsProcessed() {
+	// Requests are kept in cache while device is paused, and will be acted on once it is resumed:
+	if request.IsProcessed() || m.Paused() {
 		return
 	}
 
 	var (
-		handler = func(readings engine.ReadingResults) {
-			m.postReadings(request.AssetID, readings)
+		monitor = newExcursionMonitor(request.Limits)
+		deadband = m.newDeadband()
+		handler = func(readings engine.ReadingResults, summary map[models.Metric]model.WindowSummary) {
+			var (
+				changed = m.checkExcursions(ctx, request, monitor, readings)
+				record = m.newReadingsRecord(request.AssetID, readings)
+				force = changed || summary != nil
+			)
+
+			record.Excursions = monitor.excursions()
+			record.Summary = summary
+
+			// Excursions and period summaries are always reported, otherwise readings are posted only by exception:
+			if post, suppressed := deadband.Pass(record.Values, force || len(record.Excursions) != 0); post {
+				record.Suppressed = suppressed
+				m.postReadings(record)
+			} else {
+				shared.Logger.Debugf("Readings for asset %s are unchanged, posting is suppressed (%d in a row)",
+					request.AssetID, suppressed)
+			}
+
 			eventdriver.EmitEvent(ctx, events.RequestHandled, nil)
 		}
 	)
 
 	// Handle one-time request
 	if request.Period.Seconds() == 0 {
-		m.engine.SendRequest(handler, request.Metrics...)
+		m.engine.SendRequest(func(readings engine.ReadingResults) {
+			handler(readings, nil)
+		}, request.Metrics...)
 		m.RemoveRequirementsFromCache(request.ID)
 		return
 	}
 
-	// Otherwise subscribe receiver with given period of readings:
-	request.SetCancel(m.engine.SubscribeReceiver(ctx, handler, request.Period, request.Metrics...))
+	// Otherwise subscribe receiver with given period of readings,
+	// which are sampled with higher rate in between, when windowed aggregation is enabled:
+	var (
+		sampling = viper.GetDuration("engine.window.sample_interval")
+		receiver = func(readings engine.ReadingResults, summary map[models.Metric]model.WindowSummary) {
+			handler(readings, summary)
+			m.watchExcursions(ctx, request, monitor, deadband)
+		}
+		window *engine.Window
+		cancel context.CancelFunc
+	)
+
+	if sampling > 0 && sampling < request.Period {
+		window = engine.NewWindow(request.ID, request.Limits)
+		cancel = m.engine.SubscribeWindowedReceiver(ctx, window,
+			receiver, request.Period, sampling, request.Metrics...)
+	} else {
+		cancel = m.engine.SubscribeReceiver(ctx, func(readings engine.ReadingResults) {
+			receiver(readings, nil)
+		}, request.Period, request.Metrics...)
+	}
+
+	request.SetCancel(func() {
+		cancel()
+		monitor.stop()
+
+		if window != nil {
+			window.Discard()
+		}
+	})
+
+	m.watchExcursions(ctx, request, monitor, deadband)
+}
+
+// checkExcursions evaluates `readings` against the requirements limits of the `request`
+// and notifies on metrics going beyond limits or returning within them.
+// Returns true when any of such changes have occurred.
+func (m *EngineOperator) checkExcursions(
+	ctx context.Context,
+	request *model.SensorsReadingRequest,
+	monitor *excursionMonitor,
+	readings engine.ReadingResults,
+) bool {
+	breached, resolved := monitor.evaluate(readings)
+
+	if len(breached) != 0 {
+		shared.Logger.Warningf("Readings of %v for asset %s are beyond the requirements limits", breached, request.AssetID)
+		eventdriver.EmitEvent(ctx, events.ExcursionDetected, events.ExcursionPayload{
+			RequirementsID: request.ID,
+			AssetID:        request.AssetID,
+			Metrics:        breached,
+			Values:         readings.Filter(breached...).Values(),
+		})
+	}
+
+	if len(resolved) != 0 {
+		shared.Logger.Infof("Readings of %v for asset %s are back within the requirements limits", resolved, request.AssetID)
+		eventdriver.EmitEvent(ctx, events.ExcursionResolved, events.ExcursionPayload{
+			RequirementsID: request.ID,
+			AssetID:        request.AssetID,
+			Metrics:        resolved,
+			Values:         readings.Filter(resolved...).Values(),
+		})
+	}
+
+	return len(breached) != 0 || len(resolved) != 0
+}
+
+// watchExcursions keeps the readings of `request` sampled in between its periods,
+// with `engine.excursion.monitor_interval` while readings are within the requirements limits,
+// and with `engine.excursion.elevated_interval` while any of them remains beyond.
+//
+// Readings taken in between periods are posted immediately on excursion changes and while excursion lasts.
+func (m *EngineOperator) watchExcursions(
+	ctx context.Context,
+	request *model.SensorsReadingRequest,
+	monitor *excursionMonitor,
+	deadband *engine.Deadband,
+) {
+	if !monitor.enabled() {
+		return
+	}
+
+	var (
+		elevated = len(monitor.excursions()) != 0
+		interval = viper.GetDuration("engine.excursion.monitor_interval")
+	)
+
+	if elevated {
+		interval = viper.GetDuration("engine.excursion.elevated_interval")
+	}
+
+	if interval <= 0 || interval >= request.Period {
+		monitor.swapWatch(elevated, nil)
+		return
+	}
+
+	monitor.swapWatch(elevated, func() context.CancelFunc {
+		return m.engine.SubscribeReceiver(ctx, func(readings engine.ReadingResults) {
+			var (
+				changed = m.checkExcursions(ctx, request, monitor, readings)
+				excursions = monitor.excursions()
+			)
+
+			if changed || len(excursions) != 0 {
+				record := m.newReadingsRecord(request.AssetID, readings)
+				record.Excursions = excursions
+				_, record.Suppressed = deadband.Pass(record.Values, true)
+				m.postReadings(record)
+			}
+
+			m.watchExcursions(ctx, request, monitor, deadband)
+		}, interval, request.Metrics...)
+	})
+}
+
+// newDeadband constructs engine.Deadband for report-by-exception posting of the periodic request readings.
+func (m *EngineOperator) newDeadband() *engine.Deadband {
+	var (
+		deadbandConfig config.DeadbandConfig
+	)
+
+	// Deadband config contains map, which would be shadowed by env bindings of shared.UnmarshalFromConfig:
+	if err := viper.UnmarshalKey("engine.deadband", &deadbandConfig); err != nil {
+		shared.Logger.Error(errors.Wrap(err, "failed to parse readings deadband config"))
+	}
+
+	return engine.NewDeadbandFromConfig(deadbandConfig)
 }
 
 func (m *EngineOperator) actOnCachedRequests(ctx context.Context) {
@@ -115,26 +350,52 @@ func (m *EngineOperator) actOnCachedRequests(ctx context.Context) {
 	}
 }
 
-func (m *EngineOperator) postReadings(assetID string, readings engine.ReadingResults) {
+// pause cancels receivers of all cached requests, leaving them in cache, and puts sensors into standby.
+func (m *EngineOperator) pause() {
+	for _, request := range m.GetCachedRequirements() {
+		request.Cancel()
+	}
+
+	m.SensorsRegistry().Close()
+
+	shared.Logger.Infof("Sensors reading is paused, %d requests are put on hold", len(m.GetCachedRequirements()))
+}
+
+// newReadingsRecord forms model.MetricReadingsRecord from the `readings` for the asset with given `assetID`.
+func (m *EngineOperator) newReadingsRecord(assetID string, readings engine.ReadingResults) model.MetricReadingsRecord {
 	var (
-		ctx = context.Background()
-		record = models.MetricReadings{
-			AssetID:   assetID,
-			DeviceID:  m.ID(),
-			Timestamp: time.Now(),
-			Values:    readings,
+		record = model.MetricReadingsRecord{
+			MetricReadings: models.MetricReadings{
+				AssetID:   assetID,
+				DeviceID:  m.ID(),
+				Timestamp: time.Now(),
+				Values:    readings.Values(),
+			},
+			Meta: make(map[models.Metric]model.ReadingMeta, len(readings)),
 		}
 	)
 
-	if len(readings) == 0 {
-		shared.Logger.Warningf("No metrics was read for asset %s, posting is skipped", assetID)
+	for metric, result := range readings {
+		record.Meta[metric] = model.NewReadingMeta(result)
+	}
+
+	return record
+}
+
+func (m *EngineOperator) postReadings(record model.MetricReadingsRecord) {
+	var (
+		ctx = context.Background()
+	)
+
+	if len(record.Values) == 0 {
+		shared.Logger.Warningf("No metrics was read for asset %s, posting is skipped", record.AssetID)
 		return
 	}
 
 	if err := blockchain.Contracts.Readings.Post(record); err != nil {
 		if detectNetworkAbsence(err) {
 			eventdriver.EmitEvent(ctx, events.MetricReadingsPostFailed, events.MetricReadingsPostFailedPayload{
-				MetricReadings: record,
+				MetricReadingsRecord: record,
 				Error: err,
 			})
 		} else {
@@ -144,5 +405,10 @@ func (m *EngineOperator) postReadings(assetID string, readings engine.ReadingRes
 		return
 	}
 
-	shared.Logger.Debugf("Readings for asset %s was posted with => %s", assetID, utils.Prettify(readings))
+	shared.Logger.Debugf("Readings for asset %s was posted with => %s", record.AssetID, utils.Prettify(record.Values))
+}
+
+// Stats returns snapshot of the sensors reading engine performance statistics.
+func (m *EngineOperator) Stats() engine.EngineStats {
+	return m.engine.Stats()
 }
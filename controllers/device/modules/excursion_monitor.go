@@ -0,0 +1,130 @@
+package modules
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/controllers/engine"
+)
+
+// excursionMonitor evaluates readings of the single model.SensorsReadingRequest against its requirements limits
+// and keeps track of the metrics, which readings are currently beyond those limits.
+type excursionMonitor struct {
+	mutex    sync.Mutex
+	limits   models.RequirementsMap
+	breached map[models.Metric]time.Time
+	watch    context.CancelFunc
+	elevated bool
+	stopped  bool
+}
+
+// newExcursionMonitor constructs new excursionMonitor instance for given requirements `limits`.
+func newExcursionMonitor(limits models.RequirementsMap) *excursionMonitor {
+	return &excursionMonitor{
+		limits:   limits,
+		breached: make(map[models.Metric]time.Time),
+	}
+}
+
+// enabled determines whether there are any limits to evaluate readings against.
+func (em *excursionMonitor) enabled() bool {
+	for _, limit := range em.limits {
+		if engine.Limited(limit) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// evaluate checks given `readings` against the requirements limits
+// and returns metrics, which have just gone beyond limits or returned within them.
+func (em *excursionMonitor) evaluate(readings engine.ReadingResults) (breached, resolved []models.Metric) {
+	em.mutex.Lock()
+	defer em.mutex.Unlock()
+
+	for metric, result := range readings {
+		limit, ok := em.limits[metric]
+		if !ok || !engine.Limited(limit) {
+			continue
+		}
+
+		_, wasBreached := em.breached[metric]
+
+		switch engine.ExceedsLimit(limit, result.Value) {
+		case wasBreached:
+			continue
+		case true:
+			em.breached[metric] = result.Timestamp
+			breached = append(breached, metric)
+		default:
+			delete(em.breached, metric)
+			resolved = append(resolved, metric)
+		}
+	}
+
+	return sortMetrics(breached), sortMetrics(resolved)
+}
+
+// excursions returns metrics, which readings are currently beyond the requirements limits.
+func (em *excursionMonitor) excursions() []models.Metric {
+	em.mutex.Lock()
+	defer em.mutex.Unlock()
+
+	var (
+		metrics = make([]models.Metric, 0, len(em.breached))
+	)
+
+	for metric := range em.breached {
+		metrics = append(metrics, metric)
+	}
+
+	return sortMetrics(metrics)
+}
+
+// swapWatch replaces watching receiver with the one subscribed by `subscribe`,
+// unless monitor is already stopped or the receiver for the same `elevated` mode is already running.
+func (em *excursionMonitor) swapWatch(elevated bool, subscribe func() context.CancelFunc) {
+	em.mutex.Lock()
+	defer em.mutex.Unlock()
+
+	if em.stopped || em.watch != nil && em.elevated == elevated {
+		return
+	}
+
+	if em.watch != nil {
+		em.watch()
+		em.watch = nil
+	}
+
+	em.elevated = elevated
+
+	if subscribe != nil {
+		em.watch = subscribe()
+	}
+}
+
+// stop cancels watching receiver and prevents subscribing the new one.
+func (em *excursionMonitor) stop() {
+	em.mutex.Lock()
+	defer em.mutex.Unlock()
+
+	if em.watch != nil {
+		em.watch()
+		em.watch = nil
+	}
+
+	em.stopped = true
+}
+
+func sortMetrics(metrics []models.Metric) []models.Metric {
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i] < metrics[j]
+	})
+
+	return metrics
+}
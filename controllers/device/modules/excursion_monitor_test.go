@@ -0,0 +1,56 @@
+package modules
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/controllers/engine"
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+)
+
+func TestExcursionMonitorEvaluate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		limit    models.Requirement
+		values   []float64
+		breached []int
+		resolved []int
+	}{
+		{"zero lower bound", models.Requirement{MinLimit: 0, MaxLimit: 8}, []float64{4, -2, -1, 3}, []int{1}, []int{3}},
+		{"zero upper bound", models.Requirement{MinLimit: -25, MaxLimit: 0}, []float64{-18, 2, -5}, []int{1}, []int{2}},
+		{"lower bound only", models.Requirement{MinLimit: 2, MaxLimit: 0}, []float64{20, 1, 40}, []int{1}, []int{2}},
+		{"not configured", models.Requirement{}, []float64{-100, 100}, nil, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				em = newExcursionMonitor(models.RequirementsMap{metrics.Temperature: tc.limit})
+				breached, resolved []int
+			)
+
+			for i, value := range tc.values {
+				b, r := em.evaluate(engine.ReadingResults{
+					metrics.Temperature: sensor.ReadingResult{Value: value},
+				})
+
+				if len(b) != 0 {
+					breached = append(breached, i)
+				}
+
+				if len(r) != 0 {
+					resolved = append(resolved, i)
+				}
+			}
+
+			if fmt.Sprint(breached) != fmt.Sprint(tc.breached) {
+				t.Errorf("expected excursions to start at readings %v, got %v", tc.breached, breached)
+			}
+
+			if fmt.Sprint(resolved) != fmt.Sprint(tc.resolved) {
+				t.Errorf("expected excursions to end at readings %v, got %v", tc.resolved, resolved)
+			}
+		})
+	}
+}
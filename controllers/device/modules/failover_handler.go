@@ -7,10 +7,10 @@ import (
 	fabricStatus "github.com/hyperledger/fabric-sdk-go/pkg/common/errors/status"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
-	"github.com/timoth-y/chainmetric-core/models"
 	"github.com/timoth-y/chainmetric-core/utils"
 	"github.com/timoth-y/chainmetric-iot/controllers/device"
 	"github.com/timoth-y/chainmetric-iot/controllers/storage"
+	"github.com/timoth-y/chainmetric-iot/model"
 	"github.com/timoth-y/chainmetric-iot/model/events"
 	"github.com/timoth-y/chainmetric-iot/network/blockchain"
 	"github.com/timoth-y/chainmetric-iot/shared"
@@ -46,7 +46,7 @@ func (m *FailoverHandler) Start(ctx context.Context) {
 		// Listen to metric readings failures
 		eventdriver.SubscribeHandler(events.MetricReadingsPostFailed, func(ctx context.Context, v interface{}) error {
 			if payload, ok := v.(events.MetricReadingsPostFailedPayload); ok {
-				m.handleFailedToPostReadings(payload.MetricReadings)
+				m.handleFailedToPostReadings(payload.MetricReadingsRecord)
 				return nil
 			}
 
@@ -59,7 +59,7 @@ func (m *FailoverHandler) Start(ctx context.Context) {
 }
 
 
-func (m *FailoverHandler) handleFailedToPostReadings(readings models.MetricReadings) {
+func (m *FailoverHandler) handleFailedToPostReadings(readings model.MetricReadingsRecord) {
 	m.pingNetworkConnection()
 
 	if err := storage.CacheReadings(readings); err != nil {
@@ -90,7 +90,7 @@ func (m *FailoverHandler) pingNetworkConnection() {
 
 // tryRepostCachedReadings makes attempt to repost cached during network absence sensor readings data.
 func (m *FailoverHandler) tryRepostCachedReadings() {
-	storage.IterateOverCachedReadings(m.ctx, func(key string, record models.MetricReadings) (toBreak bool, err error) {
+	storage.IterateOverCachedReadings(m.ctx, func(key string, record model.MetricReadingsRecord) (toBreak bool, err error) {
 		if err = blockchain.Contracts.Readings.Post(record); err != nil {
 			if detectNetworkAbsence(err) {
 				m.pingNetworkConnection()
@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/timoth-y/chainmetric-iot/controllers/device"
 	"github.com/timoth-y/chainmetric-iot/controllers/gui"
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
 	"github.com/timoth-y/chainmetric-iot/model/events"
 	"github.com/timoth-y/chainmetric-iot/shared"
 	"github.com/timoth-y/go-eventdriver"
@@ -68,6 +69,55 @@ func (m *GUIRenderer) Start(ctx context.Context) {
 			return eventdriver.ErrIncorrectPayload
 		})
 
+		// Act on sensors health changes to view degradation notification:
+		eventdriver.SubscribeHandler(events.SensorDegraded, func(_ context.Context, v interface{}) error {
+			if payload, ok := v.(events.SensorDegradedPayload); ok {
+				m.renderNotification(fmt.Sprintf("%s is failing, disabled for %v", sensor.DisplayName(payload.SensorID), payload.RetryIn),
+					"warning",
+				)
+				return nil
+			}
+
+			return eventdriver.ErrIncorrectPayload
+		})
+
+		eventdriver.SubscribeHandler(events.SensorRecovered, func(_ context.Context, v interface{}) error {
+			if payload, ok := v.(events.SensorRecoveredPayload); ok {
+				m.renderNotification(fmt.Sprintf("%s has recovered", sensor.DisplayName(payload.SensorID)), "success")
+				return nil
+			}
+
+			return eventdriver.ErrIncorrectPayload
+		})
+
+		eventdriver.SubscribeHandler(events.ExcursionDetected, func(_ context.Context, v interface{}) error {
+			if payload, ok := v.(events.ExcursionPayload); ok {
+				m.renderNotification(fmt.Sprintf("%v is beyond the limits", payload.Metrics), "warning")
+				return nil
+			}
+
+			return eventdriver.ErrIncorrectPayload
+		})
+
+		eventdriver.SubscribeHandler(events.ExcursionResolved, func(_ context.Context, v interface{}) error {
+			if payload, ok := v.(events.ExcursionPayload); ok {
+				m.renderNotification(fmt.Sprintf("%v is back within the limits", payload.Metrics), "success")
+				return nil
+			}
+
+			return eventdriver.ErrIncorrectPayload
+		})
+
+		eventdriver.SubscribeHandler(events.DeviceSensingPaused, func(_ context.Context, _ interface{}) error {
+			m.renderNotification("Sensing has been paused", "warning")
+			return nil
+		})
+
+		eventdriver.SubscribeHandler(events.DeviceSensingResumed, func(_ context.Context, _ interface{}) error {
+			m.renderNotification("Sensing has been resumed", "success")
+			return nil
+		})
+
 		m.renderStats(true)
 		m.renderLoop(ctx)
 	})
@@ -106,9 +156,14 @@ func (m *GUIRenderer) renderStats(reread bool) {
 
 	builder.WriteString(fmt.Sprintf("IP: %s\n", m.Specs().IPAddress))
 	builder.WriteString(fmt.Sprintf("Supported: %d metrics\n", len(m.Specs().Supports)))
-	builder.WriteString(fmt.Sprintf("Thoughput: %d requests\\min",
-		int(throughput[len(m.requestsThroughput) - 1]),
-	))
+
+	if m.Paused() {
+		builder.WriteString("Sensing is paused")
+	} else {
+		builder.WriteString(fmt.Sprintf("Thoughput: %d requests\\min",
+			int(throughput[len(m.requestsThroughput) - 1]),
+		))
+	}
 
 	gui.SetBatteryLevel(m.Battery().Level)
 	gui.RenderWithChart(builder.String(), m.requestsThroughput...)
@@ -122,13 +177,18 @@ func (m *GUIRenderer) renderHotswapNotification(event events.SensorsRegisterChan
 	var (
 		builder = strings.Builder{}
 		attached []string
+		removed []string
 	)
 
 	m.viewLock.Lock()
 	m.viewLock.Unlock()
 
 	for i := range event.Added {
-		attached = append(attached, event.Added[i].ID())
+		attached = append(attached, sensor.DisplayName(event.Added[i].ID()))
+	}
+
+	for i := range event.Removed {
+		removed = append(removed, sensor.DisplayName(event.Removed[i]))
 	}
 
 	if len(attached) > 0 {
@@ -157,12 +217,16 @@ func (m *GUIRenderer) renderHotswapNotification(event events.SensorsRegisterChan
 
 		builder.WriteString("\n")
 		builder.WriteString(fmt.Sprintf("%s %s removed",
-			strings.Join(event.Removed, ","),
+			strings.Join(removed, ","),
 			word,
 		))
 	}
 
-	gui.RenderTextWithIcon(builder.String(), "hotswap")
+	m.renderNotification(builder.String(), "hotswap")
+}
+
+func (m *GUIRenderer) renderNotification(text, icon string) {
+	gui.RenderTextWithIcon(text, icon)
 
 	go func() {
 		time.Sleep(6 * time.Second)
@@ -70,11 +70,18 @@ func (m *HotswapDetector) handleHotswap(ctx context.Context) error {
 		}
 	}
 
+	// Static sensors can't be detected, thus are considered to be always attached:
+	for id, s := range staticSensors {
+		if !detectedSensors.Exists(id) {
+			detectedSensors[id] = s
+		}
+	}
+
 	for id := range registeredSensors {
-		if !detectedSensors.Exists(id) && !m.contains(staticSensors, id) {
+		if !detectedSensors.Exists(id) {
 			payload.Removed = append(payload.Removed, id)
 			isChanges = true
-			shared.Logger.Debugf("Hotswap: %s sensor was detached from the device", id)
+			shared.Logger.Debugf("Hotswap: %s sensor was detached from the device", sensor.DisplayName(id))
 		}
 	}
 
@@ -82,19 +89,15 @@ func (m *HotswapDetector) handleHotswap(ctx context.Context) error {
 		if !registeredSensors.Exists(id) {
 			payload.Added = append(payload.Added, detectedSensors[id])
 			isChanges = true
-			shared.Logger.Debugf("Hotswap: %s sensor was attached to the device", id)
+			shared.Logger.Debugf("Hotswap: %s sensor was attached to the device", sensor.DisplayName(id))
 		}
 	}
 
 	if isChanges {
-		eventdriver.EmitEvent(ctx, events.SensorsRegisterChanged, payload)
+		// Register is updated prior to the event, so that its handlers would observe the changes:
 		m.UpdateSensorsRegister(payload.Added, payload.Removed)
+		eventdriver.EmitEvent(ctx, events.SensorsRegisterChanged, payload)
 	}
 
 	return nil
 }
-
-func (m *HotswapDetector) contains(register map[string]sensor.Sensor, id string) bool {
-	_, contains := register[id]
-	return contains
-}
@@ -65,6 +65,23 @@ func (m *LifecycleManager) Start(ctx context.Context) {
 		eventdriver.SubscribeHandler(events.DeviceRemovedFromNetwork, func(_ context.Context, _ interface{}) error {
 			return errors.Wrap(m.resetDevice(true), "failed to reset device")
 		})
+
+		// Act on sensors health changes to publish degradation of the device sensing on the ledger:
+		eventdriver.SubscribeHandler(events.SensorDegraded, func(_ context.Context, v interface{}) error {
+			if payload, ok := v.(events.SensorDegradedPayload); ok {
+				return m.SetSensorQuarantined(payload.SensorID, true)
+			}
+
+			return eventdriver.ErrIncorrectPayload
+		})
+
+		eventdriver.SubscribeHandler(events.SensorRecovered, func(_ context.Context, v interface{}) error {
+			if payload, ok := v.(events.SensorRecoveredPayload); ok {
+				return m.SetSensorQuarantined(payload.SensorID, false)
+			}
+
+			return eventdriver.ErrIncorrectPayload
+		})
 	})
 }
 
@@ -89,7 +106,7 @@ func (m *LifecycleManager) logInNetwork(ctx context.Context, id string) {
 			return
 		}
 
-		specs.State = models.DeviceOnline
+		specs.State = m.OperatingState()
 
 		defer shared.MustExecute(func() error {
 			return m.SetSpecs(func(ds *model.DeviceSpecs) {
@@ -118,7 +135,7 @@ func (m *LifecycleManager) proceedToDeviceRegistration(ctx context.Context) {
 		return
 	 }
 
-	specs.State = models.DeviceOnline
+	specs.State = m.OperatingState()
 
 	ctx, cancel := context.WithTimeout(ctx, viper.GetDuration("device.register_timeout_duration"))
 
@@ -186,6 +203,8 @@ func (m *LifecycleManager) discoverDeviceSpecs() (*model.DeviceSpecs, error) {
 	return &model.DeviceSpecs{
 		Network: *netEnv,
 		Supports: m.RegisteredSensors().SupportedMetrics(),
+		Capabilities: m.RegisteredSensors().Capabilities(),
+		Quarantined: m.Specs().Quarantined,
 	}, nil
 }
 
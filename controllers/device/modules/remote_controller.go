@@ -9,9 +9,11 @@ import (
 	"github.com/timoth-y/chainmetric-core/models/requests"
 	"github.com/timoth-y/chainmetric-core/utils"
 	"github.com/timoth-y/chainmetric-iot/controllers/device"
+	"github.com/timoth-y/chainmetric-iot/model/events"
 	"github.com/timoth-y/chainmetric-iot/network/blockchain"
 	"github.com/timoth-y/chainmetric-iot/network/localnet"
 	"github.com/timoth-y/chainmetric-iot/shared"
+	"github.com/timoth-y/go-eventdriver"
 )
 
 // RemoteController implements device.Module for device.Device remote commands handling.
@@ -36,7 +38,9 @@ func (m *RemoteController) Start(ctx context.Context) {
 			func(id string, cmd models.DeviceCommand, args ...interface{}) error {
 				switch cmd {
 				case models.DevicePauseCmd:
+					m.handleSensingCmd(ctx, id, true)
 				case models.DeviceResumeCmd:
+					m.handleSensingCmd(ctx, id, false)
 				case models.DevicePairingCmd:
 					m.handleBluetoothPairingCmd(ctx, id)
 				default:
@@ -51,13 +55,46 @@ func (m *RemoteController) Start(ctx context.Context) {
 }
 
 func (m *RemoteController) handleBluetoothPairingCmd(ctx context.Context, cmdID string) {
+	var err = localnet.Pair(ctx)
+
+	if errors.Cause(err) == context.DeadlineExceeded {
+		err = nil
+	}
+
+	m.submitCommandResults(cmdID, err)
+}
+
+// handleSensingCmd pauses or resumes device sensing, depending on whether the command requires it to be `paused`.
+// Cached requirements are kept while device is paused, so that they would be handled again on resume.
+func (m *RemoteController) handleSensingCmd(ctx context.Context, cmdID string, paused bool) {
+	var (
+		event = events.DeviceSensingResumed
+	)
+
+	if paused {
+		event = events.DeviceSensingPaused
+	}
+
+	if err := m.SetPaused(paused); err != nil {
+		m.submitCommandResults(cmdID, errors.Wrap(err, "failed to change device sensing state"))
+		return
+	}
+
+	shared.Logger.Infof("Device sensing is %s by remote command", m.OperatingState())
+	eventdriver.EmitEvent(ctx, event, nil)
+
+	m.submitCommandResults(cmdID, nil)
+}
+
+// submitCommandResults submits results of the command with given `cmdID`, which is failed if `err` is not nil.
+func (m *RemoteController) submitCommandResults(cmdID string, err error) {
 	var (
 		results = requests.DeviceCommandResultsSubmitRequest{
 			Status: models.DeviceCmdCompleted,
 		}
 	)
 
-	if err := localnet.Pair(ctx); err != nil && errors.Cause(err) != context.DeadlineExceeded {
+	if err != nil {
 		results.Status = models.DeviceCmdFailed
 		results.Error = utils.StringPointer(err.Error())
 		shared.Logger.Error(err)
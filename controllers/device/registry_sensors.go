@@ -4,70 +4,102 @@ import (
 	"github.com/pkg/errors"
 	"github.com/timoth-y/chainmetric-core/models/requests"
 	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/model"
 	"github.com/timoth-y/chainmetric-iot/network/blockchain"
 	"github.com/timoth-y/chainmetric-iot/shared"
 )
 
-// RegisteredSensors returns map with sensors registered on the Device.
+// RegisteredSensors returns snapshot of the sensors registered on the Device.
 func (d *Device) RegisteredSensors() sensor.SensorsRegister {
+	return d.sensors.Snapshot()
+}
+
+// SensorsRegistry returns sensor.Registry of the Device, which is safe to be shared with other components.
+func (d *Device) SensorsRegistry() *sensor.Registry {
 	return d.sensors
 }
 
 // RegisterSensors adds given `sensors` on the Device sensors pool.
 func (d *Device) RegisterSensors(sensors ...sensor.Sensor) {
-	for i, s := range sensors {
-		d.sensors[s.ID()] = sensors[i]
-	}
-
-	if d.IsLoggedToNetwork() {
-		d.updateSupportedMetrics()
-	}
+	d.sensors.Add(sensors...)
 }
 
 // UnregisterSensor removes sensor by given `id` from the Device sensors pool.
 func (d *Device) UnregisterSensor(id string) {
-	if d.sensors.Exists(id) {
-		delete(d.sensors, id)
-	}
-
-	if d.IsLoggedToNetwork() {
-		d.updateSupportedMetrics()
-	}
+	d.sensors.Remove(id)
 }
 
 // UpdateSensorsRegister applies changes in sensor.SensorsRegister of the Device.
 func (d *Device) UpdateSensorsRegister(added []sensor.Sensor, removed []string) {
-	for i, s := range added {
-		d.sensors[s.ID()] = added[i]
+	d.sensors.Update(added, removed)
+}
+
+func (d *Device) updateSupportedMetrics() {
+	var (
+		sensors = d.sensors.Snapshot()
+		supports = sensors.SupportedMetrics()
+		capabilities = sensors.Capabilities()
+	)
+
+	if err := blockchain.Contracts.Devices.UpdateSpecs(d.ID(), model.DeviceSpecsUpdateRequest{
+		DeviceUpdateRequest: requests.DeviceUpdateRequest{
+			Supports: supports,
+		},
+		Capabilities: capabilities,
+	}); err != nil {
+		shared.Logger.Error(errors.Wrap(err, "failed to update supported metrics"))
 	}
 
-	for _, id := range removed {
-		delete(d.sensors, id)
+	d.stateMutex.Lock()
+	defer d.stateMutex.Unlock()
+
+	d.specs.Supports = supports
+	d.specs.Capabilities = capabilities
+}
+
+// SetSensorQuarantined updates quarantine state of the sensor with given `id` in blockchain network,
+// so that degradation of the Device sensing is visible on the ledger.
+func (d *Device) SetSensorQuarantined(id string, quarantined bool) error {
+	d.stateMutex.Lock()
+
+	var (
+		ids = make([]string, 0, len(d.specs.Quarantined) + 1)
+	)
+
+	for _, qid := range d.specs.Quarantined {
+		if qid != id {
+			ids = append(ids, qid)
+		}
 	}
 
-	if d.IsLoggedToNetwork() {
-		d.updateSupportedMetrics()
+	if quarantined {
+		ids = append(ids, id)
 	}
-}
 
-func (d *Device) updateSupportedMetrics() {
-	if err := blockchain.Contracts.Devices.Update(d.ID(), requests.DeviceUpdateRequest{
-		Supports: d.sensors.SupportedMetrics(),
+	d.specs.Quarantined = ids
+	d.stateMutex.Unlock()
+
+	if !d.IsLoggedToNetwork() {
+		shared.Logger.Warning("won't update sensors quarantine since device hasn't been logged yet")
+		return nil
+	}
+
+	if err := blockchain.Contracts.Devices.UpdateSpecs(d.ID(), model.DeviceSpecsUpdateRequest{
+		Quarantined: &ids,
 	}); err != nil {
-		shared.Logger.Error(errors.Wrap(err, "failed to update supported metrics"))
+		return errors.Wrap(err, "failed to update sensors quarantine")
 	}
-	d.specs.Supports = d.sensors.SupportedMetrics()
+
+	return nil
 }
 
-// StaticSensors returns map with sensors statically registered on the Device.
+// StaticSensors returns snapshot of the sensors statically registered on the Device.
 func (d *Device) StaticSensors() sensor.SensorsRegister {
-	return d.staticSensors
+	return d.staticSensors.Snapshot()
 }
 
 // RegisterStaticSensors allows to registrant static (not auto-detectable) sensors.
 func (d *Device) RegisterStaticSensors(sensors ...sensor.Sensor) *Device {
-	for i, s := range sensors {
-		d.staticSensors[s.ID()] = sensors[i]
-	}
+	d.staticSensors.Add(sensors...)
 	return d
 }
@@ -0,0 +1,358 @@
+package engine
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/model/config"
+)
+
+// Available aggregation strategies names, which can be used in configuration.
+const (
+	MedianAggregation             = "median"
+	MeanAggregation               = "mean"
+	TrimmedMeanAggregation        = "trimmed_mean"
+	MinAggregation                = "min"
+	MaxAggregation                = "max"
+	PriorityAggregation           = "priority"
+	WeightedByAccuracyAggregation = "weighted"
+)
+
+type (
+	// AggregationStrategy defines method of reducing multiply readings of the same models.Metric to a single value.
+	// Along with the value it returns readings, which have actually contributed to it.
+	AggregationStrategy interface {
+		Aggregate(readings []sensor.ReadingResult) (value float64, contributors []sensor.ReadingResult)
+	}
+
+	// AggregationFunc is a function implementing AggregationStrategy.
+	AggregationFunc func(readings []sensor.ReadingResult) (float64, []sensor.ReadingResult)
+
+	// Aggregator reduces readings collected from sensor.ReadingsPipe to ReadingResults
+	// according to the AggregationStrategy specified for each models.Metric.
+	Aggregator struct {
+		fallback   AggregationStrategy
+		strategies map[models.Metric]AggregationStrategy
+	}
+)
+
+// Aggregate calls AggregationFunc to reduce `readings` to a single value.
+func (f AggregationFunc) Aggregate(readings []sensor.ReadingResult) (float64, []sensor.ReadingResult) {
+	return f(readings)
+}
+
+// NewAggregator constructs new Aggregator instance, which uses `fallback` strategy
+// for all models.Metric without explicitly specified one.
+func NewAggregator(fallback AggregationStrategy) *Aggregator {
+	return &Aggregator{
+		fallback:   fallback,
+		strategies: make(map[models.Metric]AggregationStrategy),
+	}
+}
+
+// NewAggregatorFromConfig constructs new Aggregator instance based on given `cfg` configuration.
+func NewAggregatorFromConfig(cfg config.AggregationConfig) (*Aggregator, error) {
+	var (
+		aggregator = NewAggregator(Median())
+	)
+
+	if len(cfg.Default) != 0 {
+		fallback, err := StrategyFromConfig(config.MetricAggregationConfig{Strategy: cfg.Default})
+		if err != nil {
+			return aggregator, errors.Wrap(err, "failed to setup default aggregation strategy")
+		}
+
+		aggregator.fallback = fallback
+	}
+
+	for metric, mc := range cfg.Metrics {
+		strategy, err := StrategyFromConfig(mc)
+		if err != nil {
+			return aggregator, errors.Wrapf(err, "failed to setup aggregation strategy for '%s' metric", metric)
+		}
+
+		aggregator.WithStrategy(models.Metric(metric), strategy)
+	}
+
+	return aggregator, nil
+}
+
+// WithStrategy sets `strategy` to be used for aggregating readings of the given `metric`.
+func (a *Aggregator) WithStrategy(metric models.Metric, strategy AggregationStrategy) *Aggregator {
+	a.strategies[metric] = strategy
+	return a
+}
+
+// StrategyFor returns AggregationStrategy used for the given `metric`.
+func (a *Aggregator) StrategyFor(metric models.Metric) AggregationStrategy {
+	if strategy, ok := a.strategies[metric]; ok {
+		return strategy
+	}
+
+	return a.fallback
+}
+
+// Aggregate reduces `readings` per models.Metric to ReadingResults.
+func (a *Aggregator) Aggregate(readings map[models.Metric][]sensor.ReadingResult) ReadingResults {
+	var (
+		results = make(ReadingResults)
+	)
+
+	for metric := range readings {
+		if len(readings[metric]) != 0 {
+			results[metric] = a.reduce(metric, readings[metric])
+		}
+	}
+
+	return results
+}
+
+// reduce aggregates `readings` of the `metric` to a single sensor.ReadingResult,
+// which carries combined metadata of the readings contributed to its value:
+// sources are joined, the latest timestamp and the highest uncertainty are taken, quality flags are united,
+// and the lowest calibration version is kept, so that it won't overstate calibration of any contributor.
+func (a *Aggregator) reduce(metric models.Metric, readings []sensor.ReadingResult) sensor.ReadingResult {
+	var (
+		value, contributors = a.StrategyFor(metric).Aggregate(readings)
+		result = sensor.ReadingResult{
+			Value: value,
+		}
+		sources = make([]string, 0, len(contributors))
+		seen = make(map[string]bool)
+	)
+
+	for i := range contributors {
+		if !seen[contributors[i].Source] {
+			seen[contributors[i].Source] = true
+			sources = append(sources, contributors[i].Source)
+		}
+
+		if contributors[i].Timestamp.After(result.Timestamp) {
+			result.Timestamp = contributors[i].Timestamp
+		}
+
+		if i == 0 || contributors[i].CalibrationVersion < result.CalibrationVersion {
+			result.CalibrationVersion = contributors[i].CalibrationVersion
+		}
+
+		result.Uncertainty = math.Max(result.Uncertainty, contributors[i].Uncertainty)
+		result.Flags |= contributors[i].Flags
+	}
+
+	sort.Strings(sources)
+	result.Source = strings.Join(sources, ",")
+
+	return result
+}
+
+// StrategyFromConfig builds AggregationStrategy based on given `cfg` configuration.
+func StrategyFromConfig(cfg config.MetricAggregationConfig) (AggregationStrategy, error) {
+	switch strings.ToLower(cfg.Strategy) {
+	case MedianAggregation, "":
+		return Median(), nil
+	case MeanAggregation:
+		return Mean(), nil
+	case TrimmedMeanAggregation:
+		if cfg.Trim < 0 || cfg.Trim >= 0.5 {
+			return nil, errors.Errorf("trim ratio must be in [0, 0.5) range, got %v", cfg.Trim)
+		}
+		return TrimmedMean(cfg.Trim), nil
+	case MinAggregation:
+		return Min(), nil
+	case MaxAggregation:
+		return Max(), nil
+	case PriorityAggregation:
+		if len(cfg.Priority) == 0 {
+			return nil, errors.New("priority strategy requires at least one source to be specified")
+		}
+		return SourcePriority(cfg.Priority...), nil
+	case WeightedByAccuracyAggregation:
+		return WeightedByAccuracy(cfg.Accuracy), nil
+	default:
+		return nil, errors.Errorf("unknown aggregation strategy '%s'", cfg.Strategy)
+	}
+}
+
+// Median returns AggregationStrategy which takes median value of the readings.
+func Median() AggregationStrategy {
+	return AggregationFunc(func(readings []sensor.ReadingResult) (float64, []sensor.ReadingResult) {
+		sorted := sortedReadings(readings)
+
+		if n := len(sorted); n % 2 == 0 {
+			return (sorted[n/2 - 1].Value + sorted[n/2].Value) / 2, sorted[n/2 - 1:n/2 + 1]
+		} else {
+			return sorted[n/2].Value, sorted[n/2:n/2 + 1]
+		}
+	})
+}
+
+// Mean returns AggregationStrategy which takes arithmetic mean of the readings.
+func Mean() AggregationStrategy {
+	return AggregationFunc(func(readings []sensor.ReadingResult) (float64, []sensor.ReadingResult) {
+		return mean(values(readings)), readings
+	})
+}
+
+// TrimmedMean returns AggregationStrategy which takes arithmetic mean of the readings,
+// after discarding given `ratio` of the lowest and the highest values.
+func TrimmedMean(ratio float64) AggregationStrategy {
+	return AggregationFunc(func(readings []sensor.ReadingResult) (float64, []sensor.ReadingResult) {
+		var (
+			sorted = sortedReadings(readings)
+			trim = int(math.Floor(float64(len(sorted)) * ratio))
+			kept = sorted[trim:len(sorted) - trim]
+		)
+
+		return mean(values(kept)), kept
+	})
+}
+
+// Min returns AggregationStrategy which takes the lowest value of the readings.
+func Min() AggregationStrategy {
+	return AggregationFunc(func(readings []sensor.ReadingResult) (float64, []sensor.ReadingResult) {
+		sorted := sortedReadings(readings)
+		return sorted[0].Value, sorted[:1]
+	})
+}
+
+// Max returns AggregationStrategy which takes the highest value of the readings.
+func Max() AggregationStrategy {
+	return AggregationFunc(func(readings []sensor.ReadingResult) (float64, []sensor.ReadingResult) {
+		sorted := sortedReadings(readings)
+		return sorted[len(sorted) - 1].Value, sorted[len(sorted) - 1:]
+	})
+}
+
+// SourcePriority returns AggregationStrategy which takes value from the source sensor
+// with the highest priority, defined by its position in `sources`.
+// Readings from the sources not mentioned in the list are aggregated with Median strategy.
+func SourcePriority(sources ...string) AggregationStrategy {
+	return AggregationFunc(func(readings []sensor.ReadingResult) (float64, []sensor.ReadingResult) {
+		for _, source := range sources {
+			for i := range readings {
+				if sensor.Refers(source, readings[i].Source) {
+					return readings[i].Value, readings[i:i + 1]
+				}
+			}
+		}
+
+		return Median().Aggregate(readings)
+	})
+}
+
+// WeightedByAccuracy returns AggregationStrategy which takes weighted mean of the readings,
+// where each value is weighted by inverse square of its source sensor `accuracy`.
+// For sources, which accuracy isn't configured, the uncertainty of the reading is used instead,
+// which defaults to the accuracy declared by sensor (see sensor.Described).
+// Readings from the sources with unknown accuracy are used only when none of the sources is known.
+func WeightedByAccuracy(accuracy map[string]float64) AggregationStrategy {
+	return AggregationFunc(func(readings []sensor.ReadingResult) (float64, []sensor.ReadingResult) {
+		var (
+			sum, weights float64
+			weighted []sensor.ReadingResult
+		)
+
+		for i := range readings {
+			acc, ok := accuracyOf(accuracy, readings[i].Source)
+			if !ok {
+				acc = readings[i].Uncertainty
+			}
+
+			if acc > 0 {
+				w := 1 / (acc * acc)
+				sum += readings[i].Value * w
+				weights += w
+				weighted = append(weighted, readings[i])
+			}
+		}
+
+		if weights == 0 {
+			return Mean().Aggregate(readings)
+		}
+
+		return sum / weights, weighted
+	})
+}
+
+// accuracyOf looks up configured `accuracy` of the `source` sensor, which can be referred by any of its names.
+// Config keys are lower-cased by viper, thus names are matched case insensitively.
+func accuracyOf(accuracy map[string]float64, source string) (float64, bool) {
+	for _, name := range sensor.NamesOf(source) {
+		for configured, acc := range accuracy {
+			if strings.EqualFold(configured, name) {
+				return acc, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// drainPipe collects all readings dumped to the given `pipe`.
+func drainPipe(pipe sensor.ReadingsPipe) map[models.Metric][]sensor.ReadingResult {
+	var (
+		readings = make(map[models.Metric][]sensor.ReadingResult, len(pipe))
+	)
+
+	for metric, ch := range pipe {
+		readings[metric] = drain(ch)
+	}
+
+	return readings
+}
+
+func drain(ch chan sensor.ReadingResult) []sensor.ReadingResult {
+	var (
+		readings = make([]sensor.ReadingResult, 0)
+	)
+
+	for {
+		select {
+		case reading := <- ch:
+			readings = append(readings, reading)
+		default:
+			return readings
+		}
+	}
+}
+
+func values(readings []sensor.ReadingResult) []float64 {
+	var (
+		values = make([]float64, len(readings))
+	)
+
+	for i := range readings {
+		values[i] = readings[i].Value
+	}
+
+	return values
+}
+
+// sortedReadings returns copy of the `readings` sorted by their values.
+func sortedReadings(readings []sensor.ReadingResult) []sensor.ReadingResult {
+	sorted := make([]sensor.ReadingResult, len(readings))
+	copy(sorted, readings)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Value < sorted[j].Value
+	})
+
+	return sorted
+}
+
+func mean(values []float64) float64 {
+	var (
+		sum float64
+	)
+
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
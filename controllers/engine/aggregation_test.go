@@ -0,0 +1,97 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/model/config"
+)
+
+func TestAggregationStrategies(t *testing.T) {
+	var (
+		now = time.Now()
+		readings = []sensor.ReadingResult{
+			{Source: "HDC1080@1/0x40", Value: 21, Uncertainty: 0.2, Timestamp: now},
+			{Source: "BMP280@1/0x76", Value: 23, Uncertainty: 1, Timestamp: now.Add(time.Second)},
+			{Source: "DS18B20@28-0000", Value: 22, Uncertainty: 0.5, Timestamp: now.Add(2 * time.Second)},
+			{Source: "MOCK-I2C@1/0x88", Value: 30, Timestamp: now.Add(3 * time.Second)},
+		}
+	)
+
+	for _, tc := range []struct {
+		name     string
+		strategy AggregationStrategy
+		readings []sensor.ReadingResult
+		value    float64
+		source   string
+	}{
+		{"median", Median(), nil, 22.5, "BMP280@1/0x76,DS18B20@28-0000"},
+		{"median of odd", Median(), readings[:3], 22, "DS18B20@28-0000"},
+		{"mean", Mean(), nil, 24, "BMP280@1/0x76,DS18B20@28-0000,HDC1080@1/0x40,MOCK-I2C@1/0x88"},
+		{"trimmed mean", TrimmedMean(0.25), nil, 22.5, "BMP280@1/0x76,DS18B20@28-0000"},
+		{"min", Min(), nil, 21, "HDC1080@1/0x40"},
+		{"max", Max(), nil, 30, "MOCK-I2C@1/0x88"},
+		{"priority", SourcePriority("DS18B20", "HDC1080"), nil, 22, "DS18B20@28-0000"},
+		{"priority fallback", SourcePriority("LSM303C-A"), nil, 22.5, "BMP280@1/0x76,DS18B20@28-0000"},
+		{"weighted by uncertainty", WeightedByAccuracy(nil), nil,
+			(21/0.04 + 23/1.0 + 22/0.25) / (1/0.04 + 1/1.0 + 1/0.25), "BMP280@1/0x76,DS18B20@28-0000,HDC1080@1/0x40"},
+		{"weighted by configured accuracy", WeightedByAccuracy(map[string]float64{"mock-i2c": 1, "hdc1080": 1}), nil,
+			(21 + 23 + 22 / 0.25 + 30) / (1 + 1 + 1/0.25 + 1), "BMP280@1/0x76,DS18B20@28-0000,HDC1080@1/0x40,MOCK-I2C@1/0x88"},
+		{"weighted fallback", WeightedByAccuracy(nil), readings[3:], 30, "MOCK-I2C@1/0x88"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.readings == nil {
+				tc.readings = readings
+			}
+
+			result := NewAggregator(tc.strategy).reduce(metrics.Temperature, tc.readings)
+
+			if math.Abs(result.Value - tc.value) > 1e-9 {
+				t.Errorf("expected value %v, got %v", tc.value, result.Value)
+			}
+
+			if result.Source != tc.source {
+				t.Errorf("expected source '%s', got '%s'", tc.source, result.Source)
+			}
+		})
+	}
+}
+
+func TestAggregationMetadataOfContributors(t *testing.T) {
+	var (
+		now = time.Now()
+		readings = []sensor.ReadingResult{
+			{Source: "HDC1080@1/0x40", Value: 21, Uncertainty: 0.2, Timestamp: now, CalibrationVersion: 2},
+			{Source: "BMP280@1/0x76", Value: 23, Uncertainty: 1, Timestamp: now.Add(time.Second),
+				Flags: sensor.OutOfRange},
+		}
+		result = NewAggregator(SourcePriority("HDC1080")).reduce(metrics.Temperature, readings)
+	)
+
+	if result.Uncertainty != 0.2 || !result.Timestamp.Equal(now) || result.CalibrationVersion != 2 || result.Flags != 0 {
+		t.Errorf("expected metadata of the prioritized reading only, got %+v", result)
+	}
+}
+
+func TestAggregatorFromConfig(t *testing.T) {
+	for _, tc := range []struct {
+		strategy string
+		valid    bool
+	}{
+		{"", true},
+		{MedianAggregation, true},
+		{WeightedByAccuracyAggregation, true},
+		{PriorityAggregation, false},
+		{"unknown", false},
+	} {
+		_, err := StrategyFromConfig(config.MetricAggregationConfig{Strategy: tc.strategy})
+
+		if tc.valid != (err == nil) {
+			t.Errorf("strategy '%s': expected valid=%v, got error %v", tc.strategy, tc.valid, err)
+		}
+	}
+}
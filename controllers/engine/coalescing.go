@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models"
+)
+
+type (
+	// batch defines group of requests, which are due at the same time and have overlapping metrics,
+	// thus can be handled with a single sensors harvest.
+	batch []request
+
+	// CoalescingStats defines snapshot of counters showing how many sensor readings were saved
+	// due to coalescing overlapping requests.
+	CoalescingStats struct {
+		// Requests is a total number of handled requests.
+		Requests uint64
+		// Harvests is a number of sensors harvests performed for handled requests.
+		Harvests uint64
+		// SensorReads is a number of physical sensors reads performed.
+		SensorReads uint64
+		// SensorReadsSaved is a number of physical sensors reads,
+		// which would've been performed additionally if requests weren't coalesced.
+		SensorReadsSaved uint64
+	}
+
+	// coalescingCounters accumulates CoalescingStats in concurrency safe way.
+	coalescingCounters struct {
+		requests         uint64
+		harvests         uint64
+		sensorReads      uint64
+		sensorReadsSaved uint64
+	}
+)
+
+// metrics returns union of all models.Metric requested in batch.
+func (b batch) metrics() []models.Metric {
+	var (
+		metrics []models.Metric
+		seen = make(map[models.Metric]bool)
+	)
+
+	for _, req := range b {
+		for _, metric := range req.Metrics {
+			if !seen[metric] {
+				seen[metric] = true
+				metrics = append(metrics, metric)
+			}
+		}
+	}
+
+	return metrics
+}
+
+// interval returns the shortest non-zero interval of the periodic requests in batch,
+// or zero when batch contains only one-time requests.
+func (b batch) interval() (interval time.Duration) {
+	for _, req := range b {
+		if req.Interval > 0 && (interval == 0 || req.Interval < interval) {
+			interval = req.Interval
+		}
+	}
+
+	return
+}
+
+// String returns human-readable identification of the requests in batch.
+func (b batch) String() string {
+	var (
+		ids = make([]string, len(b))
+	)
+
+	for i := range b {
+		ids[i] = fmt.Sprintf("#%d", b[i].ID)
+	}
+
+	if len(ids) == 1 {
+		return fmt.Sprintf("request %s", ids[0])
+	}
+
+	return fmt.Sprintf("requests %s", strings.Join(ids, ", "))
+}
+
+// track counts sensors reads performed for the batch `b` and how many of them were saved,
+// based on `countSuitable` function, which determines the number of sensors each request would require alone.
+func (c *coalescingCounters) track(b batch, sensorsRead int, countSuitable func([]models.Metric) int) {
+	var (
+		required int
+	)
+
+	for _, req := range b {
+		required += countSuitable(req.Metrics)
+	}
+
+	atomic.AddUint64(&c.requests, uint64(len(b)))
+	atomic.AddUint64(&c.harvests, 1)
+	atomic.AddUint64(&c.sensorReads, uint64(sensorsRead))
+
+	if required > sensorsRead {
+		atomic.AddUint64(&c.sensorReadsSaved, uint64(required - sensorsRead))
+	}
+}
+
+func (c *coalescingCounters) snapshot() CoalescingStats {
+	return CoalescingStats{
+		Requests:         atomic.LoadUint64(&c.requests),
+		Harvests:         atomic.LoadUint64(&c.harvests),
+		SensorReads:      atomic.LoadUint64(&c.sensorReads),
+		SensorReadsSaved: atomic.LoadUint64(&c.sensorReadsSaved),
+	}
+}
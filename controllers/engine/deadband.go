@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/model/config"
+)
+
+type (
+	// DeadbandThreshold defines how much the models.Metric value must move since the last posted one
+	// to be reported again. Zero threshold isn't used, when both are zero any change is reported.
+	DeadbandThreshold struct {
+		// Absolute is the minimal change of the value in metric units.
+		Absolute float64
+		// Relative is the minimal change of the value as a fraction of the last posted one.
+		Relative float64
+	}
+
+	// Deadband implements report-by-exception mode for periodic readings of the single request:
+	// readings are suppressed unless any value moved beyond its DeadbandThreshold since the last post,
+	// or the maximum silence interval has elapsed, so that a heartbeat post is forced.
+	Deadband struct {
+		mutex      sync.Mutex
+		thresholds map[models.Metric]DeadbandThreshold
+		fallback   DeadbandThreshold
+		maxSilence time.Duration
+		last       map[models.Metric]float64
+		lastPosted time.Time
+		suppressed int
+		// now returns current time, it is replaced in tests to control the heartbeat.
+		now func() time.Time
+	}
+)
+
+// NewDeadband constructs new Deadband instance with `fallback` threshold for all metrics
+// and `maxSilence` interval, after which readings are posted regardless of changes (zero disables heartbeat).
+func NewDeadband(fallback DeadbandThreshold, maxSilence time.Duration) *Deadband {
+	return &Deadband{
+		thresholds: make(map[models.Metric]DeadbandThreshold),
+		fallback:   fallback,
+		maxSilence: maxSilence,
+		last:       make(map[models.Metric]float64),
+		now:        time.Now,
+	}
+}
+
+// NewDeadbandFromConfig constructs new Deadband instance with thresholds specified in `cfg` configuration.
+func NewDeadbandFromConfig(cfg config.DeadbandConfig) *Deadband {
+	var (
+		d = NewDeadband(DeadbandThreshold{
+			Absolute: cfg.Absolute,
+			Relative: cfg.Relative,
+		}, cfg.MaxSilence)
+	)
+
+	for metric, mc := range cfg.Metrics {
+		t := d.ThresholdFor(models.Metric(metric))
+
+		if mc.Absolute != nil {
+			t.Absolute = *mc.Absolute
+		}
+
+		if mc.Relative != nil {
+			t.Relative = *mc.Relative
+		}
+
+		d.WithThreshold(models.Metric(metric), t)
+	}
+
+	return d
+}
+
+// WithThreshold sets `threshold` to be used for checking changes of the given `metric`.
+func (d *Deadband) WithThreshold(metric models.Metric, threshold DeadbandThreshold) *Deadband {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.thresholds[metric] = threshold
+	return d
+}
+
+// ThresholdFor returns DeadbandThreshold used for checking changes of the given `metric`.
+func (d *Deadband) ThresholdFor(metric models.Metric) DeadbandThreshold {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.thresholdFor(metric)
+}
+
+func (d *Deadband) thresholdFor(metric models.Metric) DeadbandThreshold {
+	if t, ok := d.thresholds[metric]; ok {
+		return t
+	}
+
+	return d.fallback
+}
+
+// Pass determines whether the readings `values` should be posted, which is always so when `force` is true.
+// When they should, given values become the new baseline and the number of readings suppressed
+// since the previous post is returned, so that consumers could tell unchanged readings from missing ones.
+func (d *Deadband) Pass(values map[models.Metric]float64, force bool) (post bool, suppressed int) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var (
+		now = d.now()
+	)
+
+	if !force && !d.changed(values) && (d.maxSilence == 0 || now.Sub(d.lastPosted) < d.maxSilence) {
+		d.suppressed++
+		return false, d.suppressed
+	}
+
+	suppressed = d.suppressed
+
+	d.suppressed = 0
+	d.lastPosted = now
+	d.last = make(map[models.Metric]float64, len(values))
+
+	for metric, value := range values {
+		d.last[metric] = value
+	}
+
+	return true, suppressed
+}
+
+func (d *Deadband) changed(values map[models.Metric]float64) bool {
+	if len(values) != len(d.last) {
+		return true
+	}
+
+	for metric, value := range values {
+		last, ok := d.last[metric]
+		if !ok {
+			return true
+		}
+
+		var (
+			t = d.thresholdFor(metric)
+			delta = math.Abs(value - last)
+		)
+
+		switch {
+		case t.Absolute == 0 && t.Relative == 0:
+			if delta != 0 {
+				return true
+			}
+		case t.Absolute != 0 && delta >= t.Absolute:
+			return true
+		// Any move away from zero baseline is a change beyond the relative threshold:
+		case t.Relative != 0 && delta != 0 && delta >= t.Relative * math.Abs(last):
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+)
+
+func TestDeadbandThresholds(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		fallback  DeadbandThreshold
+		threshold *DeadbandThreshold
+		baseline  map[models.Metric]float64
+		next      map[models.Metric]float64
+		post      bool
+	}{
+		{"unchanged", DeadbandThreshold{},
+			nil, map[models.Metric]float64{metrics.Temperature: 20}, map[models.Metric]float64{metrics.Temperature: 20}, false},
+		{"any change without thresholds", DeadbandThreshold{},
+			nil, map[models.Metric]float64{metrics.Temperature: 20}, map[models.Metric]float64{metrics.Temperature: 20.01}, true},
+		{"within absolute threshold", DeadbandThreshold{Absolute: 0.5},
+			nil, map[models.Metric]float64{metrics.Temperature: 20}, map[models.Metric]float64{metrics.Temperature: 20.4}, false},
+		{"on absolute threshold", DeadbandThreshold{Absolute: 0.5},
+			nil, map[models.Metric]float64{metrics.Temperature: 20}, map[models.Metric]float64{metrics.Temperature: 20.5}, true},
+		{"beyond absolute threshold downwards", DeadbandThreshold{Absolute: 0.5},
+			nil, map[models.Metric]float64{metrics.Temperature: 20}, map[models.Metric]float64{metrics.Temperature: 19.4}, true},
+		{"within relative threshold", DeadbandThreshold{Relative: 0.1},
+			nil, map[models.Metric]float64{metrics.Humidity: 50}, map[models.Metric]float64{metrics.Humidity: 54}, false},
+		{"on relative threshold", DeadbandThreshold{Relative: 0.1},
+			nil, map[models.Metric]float64{metrics.Humidity: 50}, map[models.Metric]float64{metrics.Humidity: 55}, true},
+		{"relative threshold of negative value", DeadbandThreshold{Relative: 0.1},
+			nil, map[models.Metric]float64{metrics.Temperature: -20}, map[models.Metric]float64{metrics.Temperature: -18}, true},
+		{"relative threshold of unchanged zero", DeadbandThreshold{Relative: 0.1},
+			nil, map[models.Metric]float64{metrics.Luminosity: 0}, map[models.Metric]float64{metrics.Luminosity: 0}, false},
+		{"relative threshold of zero baseline", DeadbandThreshold{Relative: 0.1},
+			nil, map[models.Metric]float64{metrics.Luminosity: 0}, map[models.Metric]float64{metrics.Luminosity: 0.5}, true},
+		{"relative beyond, absolute within", DeadbandThreshold{Absolute: 1, Relative: 0.5},
+			nil, map[models.Metric]float64{metrics.Luminosity: 1}, map[models.Metric]float64{metrics.Luminosity: 1.6}, true},
+		{"absolute beyond, relative within", DeadbandThreshold{Absolute: 1, Relative: 0.5},
+			nil, map[models.Metric]float64{metrics.Luminosity: 100}, map[models.Metric]float64{metrics.Luminosity: 101}, true},
+		{"metric threshold overrides fallback", DeadbandThreshold{Absolute: 0.1}, &DeadbandThreshold{Absolute: 1},
+			map[models.Metric]float64{metrics.Temperature: 20}, map[models.Metric]float64{metrics.Temperature: 20.5}, false},
+		{"metric appeared", DeadbandThreshold{Absolute: 1}, nil,
+			map[models.Metric]float64{metrics.Temperature: 20},
+			map[models.Metric]float64{metrics.Temperature: 20, metrics.Humidity: 40}, true},
+		{"metric replaced", DeadbandThreshold{Absolute: 1}, nil,
+			map[models.Metric]float64{metrics.Temperature: 20}, map[models.Metric]float64{metrics.Humidity: 20}, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				d = NewDeadband(tc.fallback, 0)
+			)
+
+			if tc.threshold != nil {
+				d.WithThreshold(metrics.Temperature, *tc.threshold)
+			}
+
+			if post, _ := d.Pass(tc.baseline, false); !post {
+				t.Fatal("expected the first readings to be posted")
+			}
+
+			if post, _ := d.Pass(tc.next, false); post != tc.post {
+				t.Errorf("expected %v readings following %v to be posted: %v", tc.next, tc.baseline, tc.post)
+			}
+		})
+	}
+}
+
+func TestDeadbandHeartbeatAndForcedPosts(t *testing.T) {
+	var (
+		d, advance = newTestDeadband(DeadbandThreshold{Absolute: 1}, time.Minute)
+	)
+
+	// Each step advances the clock and passes temperature reading, expecting whether it is posted
+	// and the number of suppressed readings returned:
+	for _, step := range []struct {
+		name     string
+		advance  time.Duration
+		value    float64
+		force    bool
+		expected string
+	}{
+		{"first reading posted", 0, 20, false, "true 0"},
+		{"unchanged suppressed", 10 * time.Second, 20.5, false, "false 1"},
+		{"suppressed counted", 10 * time.Second, 20.2, false, "false 2"},
+		{"forced post reports suppressed", 10 * time.Second, 20.3, true, "true 2"},
+		{"forced post resets counter and baseline", 10 * time.Second, 21.2, false, "false 1"},
+		{"forced post delays heartbeat", 45 * time.Second, 20.3, false, "false 2"},
+		{"heartbeat", 5 * time.Second, 20.3, false, "true 2"},
+		{"suppressed after heartbeat", time.Second, 20.3, false, "false 1"},
+		{"forced post of unchanged reading", time.Second, 20.3, true, "true 1"},
+		{"forced post right after another", 0, 20.3, true, "true 0"},
+		{"changed reading posted", time.Second, 25, false, "true 0"},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			advance(step.advance)
+
+			post, suppressed := d.Pass(map[models.Metric]float64{metrics.Temperature: step.value}, step.force)
+
+			if outcome := fmt.Sprint(post, suppressed); outcome != step.expected {
+				t.Fatalf("expected %v reading posted and suppressed to be '%s', got '%s'",
+					step.value, step.expected, outcome)
+			}
+		})
+	}
+}
+
+func TestDeadbandWithoutHeartbeat(t *testing.T) {
+	var (
+		d, advance = newTestDeadband(DeadbandThreshold{Absolute: 1}, 0)
+		values = map[models.Metric]float64{metrics.Temperature: 20}
+	)
+
+	d.Pass(values, false)
+
+	for i := 1; i <= 10; i++ {
+		advance(time.Hour)
+
+		if post, suppressed := d.Pass(values, false); post || suppressed != i {
+			t.Fatalf("expected unchanged reading #%d to be suppressed without heartbeat, got %v, %d", i, post, suppressed)
+		}
+	}
+}
+
+// newTestDeadband constructs Deadband driven by the fake clock, which is moved forward with returned `advance` func.
+func newTestDeadband(fallback DeadbandThreshold, maxSilence time.Duration) (d *Deadband, advance func(d time.Duration)) {
+	var (
+		now = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	d = NewDeadband(fallback, maxSilence)
+	d.now = func() time.Time {
+		return now
+	}
+
+	return d, func(d time.Duration) {
+		now = now.Add(d)
+	}
+}
@@ -0,0 +1,210 @@
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+// Possible states of the sensor health check, which determine how engine should treat the sensor.
+const (
+	// sensorHealthy means that sensor can be read as usual.
+	sensorHealthy = iota
+	// sensorQuarantined means that sensor must be skipped until its re-probe is due.
+	sensorQuarantined
+	// sensorProbeDue means that sensor is quarantined, but must be re-probed before being read.
+	sensorProbeDue
+)
+
+type (
+	// healthTracker tracks consecutive failures of the sensors
+	// and quarantines ones that keep failing, with exponential backoff between re-probes.
+	healthTracker struct {
+		mutex      sync.Mutex
+		sensors    map[string]*sensorHealth
+		threshold  int
+		backoff    time.Duration
+		maxBackoff time.Duration
+		// now returns current time, it is replaced in tests to control the backoff.
+		now func() time.Time
+	}
+
+	// sensorHealth stores health state of the single sensor.
+	sensorHealth struct {
+		failures    int
+		quarantined bool
+		probing     bool
+		since       time.Time
+		probeAt     time.Time
+		backoff     time.Duration
+		lastError   error
+	}
+
+	// SensorHealth defines snapshot of the sensor health state.
+	SensorHealth struct {
+		// Failures is a number of consecutive failed or timed out readings.
+		Failures int
+		// Quarantined determines whether the sensor is excluded from readings.
+		Quarantined bool
+		// Since is a moment when sensor was quarantined.
+		Since time.Time
+		// NextProbe is a moment when quarantined sensor will be re-probed.
+		NextProbe time.Time
+		// LastError is the latest error caused sensor failure.
+		LastError error
+	}
+)
+
+// newHealthTracker constructs new healthTracker instance.
+func newHealthTracker(threshold int, backoff, maxBackoff time.Duration) *healthTracker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+
+	if maxBackoff < backoff {
+		maxBackoff = backoff
+	}
+
+	return &healthTracker{
+		sensors:    make(map[string]*sensorHealth),
+		threshold:  threshold,
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+		now:        time.Now,
+	}
+}
+
+// check determines whether sensor with given `id` can be read.
+// Once sensor re-probe is due, the first caller gets sensorProbeDue and is responsible for probing it.
+func (t *healthTracker) check(id string) int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var h, ok = t.sensors[id]
+
+	switch {
+	case !ok || !h.quarantined:
+		return sensorHealthy
+	case h.probing || t.now().Before(h.probeAt):
+		return sensorQuarantined
+	default:
+		h.probing = true
+		return sensorProbeDue
+	}
+}
+
+// success resets failures counter of the sensor with given `id`.
+func (t *healthTracker) success(id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if h, ok := t.sensors[id]; ok && !h.quarantined {
+		h.failures = 0
+		h.backoff = 0
+		h.lastError = nil
+	}
+}
+
+// failure registers failed reading of the sensor with given `id`
+// and reports whether it has caused sensor to become quarantined.
+func (t *healthTracker) failure(id string, err error) (quarantined bool, failures int, retryIn time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	h, ok := t.sensors[id]
+	if !ok {
+		h = &sensorHealth{}
+		t.sensors[id] = h
+	}
+
+	h.failures++
+	h.lastError = err
+
+	if h.quarantined || h.failures < t.threshold {
+		return false, h.failures, 0
+	}
+
+	t.quarantine(h)
+
+	return true, h.failures, h.backoff
+}
+
+// probed registers result of the quarantined sensor re-probe.
+// Successful probe releases sensor from quarantine, otherwise the backoff gets doubled.
+func (t *healthTracker) probed(id string, err error) (downtime, retryIn time.Duration) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	h, ok := t.sensors[id]
+	if !ok {
+		return 0, 0
+	}
+
+	h.probing = false
+
+	if err != nil {
+		h.lastError = err
+		t.quarantine(h)
+
+		return 0, h.backoff
+	}
+
+	// Backoff is kept until the first successful reading,
+	// so that flapping sensor would be quarantined for longer each time:
+	h.quarantined = false
+	h.failures = 0
+
+	return t.now().Sub(h.since), 0
+}
+
+// forget removes health state of the sensor with given `id`.
+func (t *healthTracker) forget(id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.sensors, id)
+}
+
+// snapshot returns health state of all tracked sensors.
+func (t *healthTracker) snapshot() map[string]SensorHealth {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var snapshot = make(map[string]SensorHealth, len(t.sensors))
+
+	for id, h := range t.sensors {
+		var health = SensorHealth{
+			Failures:    h.failures,
+			Quarantined: h.quarantined,
+			LastError:   h.lastError,
+		}
+
+		if h.quarantined {
+			health.Since = h.since
+			health.NextProbe = h.probeAt
+		}
+
+		snapshot[id] = health
+	}
+
+	return snapshot
+}
+
+func (t *healthTracker) quarantine(h *sensorHealth) {
+	switch {
+	case h.backoff == 0:
+		h.backoff = t.backoff
+	case h.backoff < t.maxBackoff:
+		h.backoff *= 2
+	}
+
+	if h.backoff > t.maxBackoff {
+		h.backoff = t.maxBackoff
+	}
+
+	if !h.quarantined {
+		h.since = t.now()
+	}
+
+	h.quarantined = true
+	h.probeAt = t.now().Add(h.backoff)
+}
@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	errSensorFault = errors.New("sensor fault")
+)
+
+func TestHealthTrackerThreshold(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		threshold   int
+		outcomes    []error
+		quarantined bool
+		failures    int
+	}{
+		{"below threshold", 3, []error{errSensorFault, errSensorFault}, false, 2},
+		{"threshold reached", 3, []error{errSensorFault, errSensorFault, errSensorFault}, true, 3},
+		{"success resets failures", 3, []error{errSensorFault, errSensorFault, nil, errSensorFault, errSensorFault},
+			false, 2},
+		{"success doesn't release quarantine", 2, []error{errSensorFault, errSensorFault, nil}, true, 2},
+		{"zero threshold quarantines on first failure", 0, []error{errSensorFault}, true, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				tracker, _ = newTestHealthTracker(tc.threshold)
+			)
+
+			for _, err := range tc.outcomes {
+				if err == nil {
+					tracker.success("HDC1080@1/0x40")
+				} else {
+					tracker.failure("HDC1080@1/0x40", err)
+				}
+			}
+
+			health := tracker.snapshot()["HDC1080@1/0x40"]
+
+			if health.Quarantined != tc.quarantined || health.Failures != tc.failures {
+				t.Errorf("expected quarantined %v with %d failures, got %+v", tc.quarantined, tc.failures, health)
+			}
+
+			expected := sensorHealthy
+			if tc.quarantined {
+				expected = sensorQuarantined
+			}
+
+			if state := tracker.check("HDC1080@1/0x40"); state != expected {
+				t.Errorf("expected sensor check state %d, got %d", expected, state)
+			}
+		})
+	}
+}
+
+func TestHealthTrackerBackoff(t *testing.T) {
+	var (
+		tracker, advance = newTestHealthTracker(1)
+	)
+
+	// Each step advances the clock and applies an action to the sensor, expecting its outcome,
+	// which is check state, quarantine and retry interval of failure, or downtime and retry interval of probe:
+	for _, step := range []struct {
+		name     string
+		advance  time.Duration
+		action   string
+		expected string
+	}{
+		{"quarantined", 0, "failure", "true 10s"},
+		{"skipped until probe", 5 * time.Second, "check", fmt.Sprint(sensorQuarantined)},
+		{"probe due", 5 * time.Second, "check", fmt.Sprint(sensorProbeDue)},
+		{"probed once", 0, "check", fmt.Sprint(sensorQuarantined)},
+		{"backoff doubled", time.Second, "probe failed", "0s 20s"},
+		{"doubled backoff not passed", 10 * time.Second, "check", fmt.Sprint(sensorQuarantined)},
+		{"doubled backoff passed", 10 * time.Second, "check", fmt.Sprint(sensorProbeDue)},
+		{"backoff doubled again", 0, "probe failed", "0s 40s"},
+		{"max backoff", 40 * time.Second, "check", fmt.Sprint(sensorProbeDue)},
+		{"backoff capped", 0, "probe failed", "0s 40s"},
+		{"probe due after max backoff", 40 * time.Second, "check", fmt.Sprint(sensorProbeDue)},
+		{"released", 0, "probe", "1m51s 0s"},
+		{"healthy", 0, "check", fmt.Sprint(sensorHealthy)},
+		{"backoff kept for flapping sensor", time.Second, "failure", "true 40s"},
+		{"released once again", 40 * time.Second, "check", fmt.Sprint(sensorProbeDue)},
+		{"released after backoff kept", 0, "probe", "40s 0s"},
+		{"backoff reset by successful reading", 0, "success", ""},
+		{"quarantined with initial backoff", 0, "failure", "true 10s"},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			var (
+				outcome string
+			)
+
+			advance(step.advance)
+
+			switch step.action {
+			case "check":
+				outcome = fmt.Sprint(tracker.check("HDC1080@1/0x40"))
+			case "failure":
+				quarantined, _, retryIn := tracker.failure("HDC1080@1/0x40", errSensorFault)
+				outcome = fmt.Sprint(quarantined, retryIn)
+			case "success":
+				tracker.success("HDC1080@1/0x40")
+			case "probe":
+				outcome = fmt.Sprint(tracker.probed("HDC1080@1/0x40", nil))
+			case "probe failed":
+				outcome = fmt.Sprint(tracker.probed("HDC1080@1/0x40", errSensorFault))
+			}
+
+			if outcome != step.expected {
+				t.Fatalf("expected '%s' outcome to be '%s', got '%s'", step.action, step.expected, outcome)
+			}
+		})
+	}
+}
+
+func TestHealthTrackerNextProbe(t *testing.T) {
+	var (
+		tracker, advance = newTestHealthTracker(1)
+		since = tracker.now()
+	)
+
+	tracker.failure("HDC1080@1/0x40", errSensorFault)
+	advance(10 * time.Second)
+	tracker.check("HDC1080@1/0x40")
+	tracker.probed("HDC1080@1/0x40", errSensorFault)
+
+	health := tracker.snapshot()["HDC1080@1/0x40"]
+
+	if !health.Since.Equal(since) {
+		t.Errorf("expected sensor to be quarantined since %v, got %v", since, health.Since)
+	}
+
+	if expected := since.Add(30 * time.Second); !health.NextProbe.Equal(expected) {
+		t.Errorf("expected sensor to be re-probed at %v, got %v", expected, health.NextProbe)
+	}
+
+	if health.LastError != errSensorFault {
+		t.Errorf("expected last error to be kept, got %v", health.LastError)
+	}
+}
+
+func TestHealthTrackerForget(t *testing.T) {
+	var (
+		tracker, _ = newTestHealthTracker(2)
+	)
+
+	for _, id := range []string{"HDC1080@1/0x40", "MAX44009@1/0x4A"} {
+		tracker.failure(id, errSensorFault)
+		tracker.failure(id, errSensorFault)
+	}
+
+	tracker.forget("HDC1080@1/0x40")
+
+	if _, ok := tracker.snapshot()["HDC1080@1/0x40"]; ok {
+		t.Error("expected health state of removed sensor to be forgotten")
+	}
+
+	if tracker.check("HDC1080@1/0x40") != sensorHealthy {
+		t.Error("expected forgotten sensor to be healthy")
+	}
+
+	if tracker.check("MAX44009@1/0x4A") != sensorQuarantined {
+		t.Error("expected other sensors to stay quarantined")
+	}
+
+	if quarantined, failures, _ := tracker.failure("HDC1080@1/0x40", errSensorFault); quarantined || failures != 1 {
+		t.Errorf("expected failures of forgotten sensor to be counted from scratch, got %d", failures)
+	}
+}
+
+// newTestHealthTracker constructs healthTracker with 10s backoff capped at 40s,
+// driven by the fake clock, which is moved forward with returned `advance` func.
+func newTestHealthTracker(threshold int) (tracker *healthTracker, advance func(d time.Duration)) {
+	var (
+		now = time.Date(2021, 6, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	tracker = newHealthTracker(threshold, 10 * time.Second, 40 * time.Second)
+	tracker.now = func() time.Time {
+		return now
+	}
+
+	return tracker, func(d time.Duration) {
+		now = now.Add(d)
+	}
+}
@@ -0,0 +1,41 @@
+package engine
+
+import (
+	"github.com/timoth-y/chainmetric-core/models"
+)
+
+// Limited determines whether the `limit` requirement has any bounds to evaluate readings against.
+// Requirement with both bounds being zero is considered not configured.
+func Limited(limit models.Requirement) bool {
+	return limit.MinLimit != 0 || limit.MaxLimit != 0
+}
+
+// BelowLimit determines whether the `value` is below the `limit` lower bound.
+//
+// Both bounds are checked, including the zero ones (e.g. 0..8 °C), just like the ledger does.
+// The only exception is the zero lower bound of the inverted range (e.g. 0..-18 °C),
+// which can't be satisfied and thus marks the requirement as the upper bound only.
+func BelowLimit(limit models.Requirement, value float64) bool {
+	if !Limited(limit) || limit.MinLimit == 0 && limit.MaxLimit < 0 {
+		return false
+	}
+
+	return value < limit.MinLimit
+}
+
+// AboveLimit determines whether the `value` is above the `limit` upper bound.
+//
+// Same as with BelowLimit, zero upper bound of the inverted range (e.g. 2..0 °C)
+// marks the requirement as the lower bound only.
+func AboveLimit(limit models.Requirement, value float64) bool {
+	if !Limited(limit) || limit.MaxLimit == 0 && limit.MinLimit > 0 {
+		return false
+	}
+
+	return value > limit.MaxLimit
+}
+
+// ExceedsLimit determines whether the `value` is beyond any of the `limit` bounds.
+func ExceedsLimit(limit models.Requirement, value float64) bool {
+	return BelowLimit(limit, value) || AboveLimit(limit, value)
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/timoth-y/chainmetric-core/models"
+)
+
+func TestRequirementLimits(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		limit models.Requirement
+		value float64
+		below bool
+		above bool
+	}{
+		{"not configured", models.Requirement{}, -100, false, false},
+		{"within range", models.Requirement{MinLimit: 2, MaxLimit: 8}, 5, false, false},
+		{"below range", models.Requirement{MinLimit: 2, MaxLimit: 8}, 1, true, false},
+		{"above range", models.Requirement{MinLimit: 2, MaxLimit: 8}, 9, false, true},
+		{"below zero lower bound", models.Requirement{MinLimit: 0, MaxLimit: 8}, -2, true, false},
+		{"at zero lower bound", models.Requirement{MinLimit: 0, MaxLimit: 8}, 0, false, false},
+		{"above zero upper bound", models.Requirement{MinLimit: -25, MaxLimit: 0}, 1, false, true},
+		{"below negative lower bound", models.Requirement{MinLimit: -25, MaxLimit: 0}, -30, true, false},
+		{"lower bound only", models.Requirement{MinLimit: 2, MaxLimit: 0}, 20, false, false},
+		{"below lower bound only", models.Requirement{MinLimit: 2, MaxLimit: 0}, 1, true, false},
+		{"upper bound only", models.Requirement{MinLimit: 0, MaxLimit: -18}, -30, false, false},
+		{"above upper bound only", models.Requirement{MinLimit: 0, MaxLimit: -18}, -10, false, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if below := BelowLimit(tc.limit, tc.value); below != tc.below {
+				t.Errorf("expected below limit to be %v", tc.below)
+			}
+
+			if above := AboveLimit(tc.limit, tc.value); above != tc.above {
+				t.Errorf("expected above limit to be %v", tc.above)
+			}
+
+			if exceeds := ExceedsLimit(tc.limit, tc.value); exceeds != (tc.below || tc.above) {
+				t.Errorf("expected exceeding limit to be %v", tc.below || tc.above)
+			}
+		})
+	}
+}
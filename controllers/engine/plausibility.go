@@ -0,0 +1,221 @@
+package engine
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/model/config"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// rebaselineAfter is a number of consecutive rate-of-change rejections from the same source,
+// after which the value is considered to be a genuine step change rather than a spike.
+const rebaselineAfter = 3
+
+type (
+	// PlausibilityLimits defines physical bounds for the models.Metric values.
+	PlausibilityLimits struct {
+		// Min is the lowest physically possible value.
+		Min float64
+		// Max is the highest physically possible value.
+		Max float64
+		// MaxRate is the highest possible change of value per second, zero means it isn't limited.
+		MaxRate float64
+	}
+
+	// Plausibility rejects sensor readings, which are out of physical limits of the models.Metric,
+	// or which have changed faster than it is possible since the previous reading from the same source.
+	Plausibility struct {
+		mutex    sync.Mutex
+		limits   map[models.Metric]PlausibilityLimits
+		last     map[sampleKey]sensor.ReadingResult
+		spikes   map[sampleKey]int
+		rejected map[string]uint64
+	}
+
+	sampleKey struct {
+		source string
+		metric models.Metric
+	}
+)
+
+// Unbounded returns PlausibilityLimits, which accept any finite value.
+func Unbounded() PlausibilityLimits {
+	return PlausibilityLimits{
+		Min: math.Inf(-1),
+		Max: math.Inf(1),
+	}
+}
+
+// DefaultPlausibilityLimits returns built-in physical bounds for the metrics with well-known units.
+func DefaultPlausibilityLimits() map[models.Metric]PlausibilityLimits {
+	return map[models.Metric]PlausibilityLimits{
+		metrics.Temperature:           {Min: -60, Max: 150},
+		metrics.Humidity:              {Min: 0, Max: 100},
+		metrics.Luminosity:            {Min: 0, Max: 188000},
+		metrics.AirCO2Concentration:   {Min: 0, Max: 32768},
+		metrics.AirTVOCsConcentration: {Min: 0, Max: 32768},
+		metrics.HeartRate:             {Min: 0, Max: 300},
+		metrics.BloodOxidation:        {Min: 0, Max: 100},
+	}
+}
+
+// NewPlausibility constructs new Plausibility instance with given `limits`.
+// Readings of the metrics without limits specified are only checked for being finite.
+func NewPlausibility(limits map[models.Metric]PlausibilityLimits) *Plausibility {
+	p := &Plausibility{
+		limits:   make(map[models.Metric]PlausibilityLimits, len(limits)),
+		last:     make(map[sampleKey]sensor.ReadingResult),
+		spikes:   make(map[sampleKey]int),
+		rejected: make(map[string]uint64),
+	}
+
+	for metric, l := range limits {
+		p.limits[metric] = l
+	}
+
+	return p
+}
+
+// NewPlausibilityFromConfig constructs new Plausibility instance with DefaultPlausibilityLimits
+// overridden by ones specified in `cfg` configuration.
+func NewPlausibilityFromConfig(cfg config.PlausibilityConfig) *Plausibility {
+	var (
+		p = NewPlausibility(DefaultPlausibilityLimits())
+	)
+
+	for metric, mc := range cfg.Metrics {
+		l := p.LimitsFor(models.Metric(metric))
+
+		if mc.Min != nil {
+			l.Min = *mc.Min
+		}
+
+		if mc.Max != nil {
+			l.Max = *mc.Max
+		}
+
+		if mc.MaxRate != nil {
+			l.MaxRate = *mc.MaxRate
+		}
+
+		p.WithLimits(models.Metric(metric), l)
+	}
+
+	return p
+}
+
+// WithLimits sets `limits` to be used for checking readings of the given `metric`.
+func (p *Plausibility) WithLimits(metric models.Metric, limits PlausibilityLimits) *Plausibility {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.limits[metric] = limits
+	return p
+}
+
+// LimitsFor returns PlausibilityLimits used for the given `metric`.
+func (p *Plausibility) LimitsFor(metric models.Metric) PlausibilityLimits {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.limitsFor(metric)
+}
+
+// Filter returns only plausible `readings`, while rejected ones are counted and logged per source sensor.
+func (p *Plausibility) Filter(readings map[models.Metric][]sensor.ReadingResult) map[models.Metric][]sensor.ReadingResult {
+	var (
+		filtered = make(map[models.Metric][]sensor.ReadingResult, len(readings))
+	)
+
+	for metric := range readings {
+		for _, reading := range readings[metric] {
+			if err := p.check(metric, reading); err != nil {
+				shared.Logger.Warningf("%s: implausible '%s' reading rejected: %v", reading.Source, metric, err)
+				continue
+			}
+
+			filtered[metric] = append(filtered[metric], reading)
+		}
+	}
+
+	return filtered
+}
+
+// Rejected returns number of rejected readings per source sensor.
+func (p *Plausibility) Rejected() map[string]uint64 {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var (
+		rejected = make(map[string]uint64, len(p.rejected))
+	)
+
+	for source, count := range p.rejected {
+		rejected[source] = count
+	}
+
+	return rejected
+}
+
+func (p *Plausibility) check(metric models.Metric, reading sensor.ReadingResult) (err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	defer func() {
+		if err != nil {
+			p.rejected[reading.Source]++
+		}
+	}()
+
+	var (
+		limits = p.limitsFor(metric)
+		key = sampleKey{reading.Source, metric}
+	)
+
+	if math.IsNaN(reading.Value) || math.IsInf(reading.Value, 0) {
+		return errors.Errorf("value %v isn't a number", reading.Value)
+	}
+
+	if reading.Value < limits.Min || reading.Value > limits.Max {
+		return errors.Errorf("value %v is out of [%v, %v] bounds", reading.Value, limits.Min, limits.Max)
+	}
+
+	if last, ok := p.last[key]; ok && limits.MaxRate > 0 {
+		if rate := changeRate(last, reading); rate > limits.MaxRate && p.spikes[key] < rebaselineAfter - 1 {
+			p.spikes[key]++
+			return errors.Errorf("value changed with rate %.3g/s, while at most %v/s is possible", rate, limits.MaxRate)
+		}
+	}
+
+	p.last[key] = reading
+	p.spikes[key] = 0
+
+	return nil
+}
+
+func (p *Plausibility) limitsFor(metric models.Metric) PlausibilityLimits {
+	if l, ok := p.limits[metric]; ok {
+		return l
+	}
+
+	return Unbounded()
+}
+
+func changeRate(prev, next sensor.ReadingResult) float64 {
+	var (
+		elapsed = next.Timestamp.Sub(prev.Timestamp)
+	)
+
+	if elapsed < time.Millisecond {
+		elapsed = time.Millisecond
+	}
+
+	return math.Abs(next.Value - prev.Value) / elapsed.Seconds()
+}
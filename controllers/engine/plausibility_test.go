@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+func TestDefaultPlausibilityLimits(t *testing.T) {
+	shared.Logger = logging.MustGetLogger("test")
+
+	var (
+		p = NewPlausibility(DefaultPlausibilityLimits())
+	)
+
+	for _, tc := range []struct {
+		name   string
+		metric models.Metric
+		value  float64
+		valid  bool
+	}{
+		{"room temperature", metrics.Temperature, 21.5, true},
+		{"lowest temperature", metrics.Temperature, -60, true},
+		{"temperature below bounds", metrics.Temperature, -61, false},
+		{"highest temperature", metrics.Temperature, 150, true},
+		{"temperature above bounds", metrics.Temperature, 151, false},
+		{"humidity", metrics.Humidity, 55, true},
+		{"negative humidity", metrics.Humidity, -1, false},
+		{"humidity above 100%", metrics.Humidity, 100.5, false},
+		{"direct sunlight", metrics.Luminosity, 120000, true},
+		{"luminosity above bounds", metrics.Luminosity, 200000, false},
+		{"CO2 above sensor range", metrics.AirCO2Concentration, 40000, false},
+		{"TVOCs", metrics.AirTVOCsConcentration, 120, true},
+		{"heart rate above bounds", metrics.HeartRate, 320, false},
+		{"blood oxidation", metrics.BloodOxidation, 98, true},
+		{"metric without bounds", metrics.Pressure, 1e9, true},
+		{"not a number", metrics.Temperature, math.NaN(), false},
+		{"infinite value of metric without bounds", metrics.Pressure, math.Inf(1), false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			filtered := p.Filter(map[models.Metric][]sensor.ReadingResult{
+				tc.metric: {{Source: tc.name, Value: tc.value, Timestamp: time.Now()}},
+			})
+
+			if valid := len(filtered[tc.metric]) != 0; valid != tc.valid {
+				t.Errorf("expected %v '%s' reading valid to be %v", tc.value, tc.metric, tc.valid)
+			}
+
+			if rejected := p.Rejected()[tc.name]; (rejected == 0) != tc.valid {
+				t.Errorf("expected only rejected reading to be counted, got %d", rejected)
+			}
+		})
+	}
+}
+
+func TestPlausibilityRateOfChange(t *testing.T) {
+	shared.Logger = logging.MustGetLogger("test")
+
+	var (
+		p = NewPlausibility(nil).WithLimits(metrics.Temperature, PlausibilityLimits{
+			Min: -60, Max: 150, MaxRate: 1,
+		})
+		start = time.Now()
+	)
+
+	// Readings are sampled each second, while temperature can't change faster than 1 °C per second:
+	for i, step := range []struct {
+		name     string
+		value    float64
+		accepted bool
+	}{
+		{"first reading", 20, true},
+		{"gradual change", 20.5, true},
+		{"spike", 35, false},
+		{"back to normal", 21, true},
+		{"step change", 30, false},
+		{"step change sustained", 30, false},
+		{"rebaselined after sustained step", 30, true},
+		{"gradual change after rebaseline", 30.5, true},
+		{"fall", 10, false},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			filtered := p.Filter(map[models.Metric][]sensor.ReadingResult{
+				metrics.Temperature: {{
+					Source:    "HDC1080@1/0x40",
+					Value:     step.value,
+					Timestamp: start.Add(time.Duration(i) * time.Second),
+				}},
+			})
+
+			if accepted := len(filtered[metrics.Temperature]) != 0; accepted != step.accepted {
+				t.Fatalf("expected %v reading accepted to be %v", step.value, step.accepted)
+			}
+		})
+	}
+
+	if rejected := p.Rejected()["HDC1080@1/0x40"]; rejected != 4 {
+		t.Errorf("expected 4 readings to be rejected, got %d", rejected)
+	}
+}
+
+func TestPlausibilityPerSensorIsolation(t *testing.T) {
+	shared.Logger = logging.MustGetLogger("test")
+
+	var (
+		p = NewPlausibility(nil).
+			WithLimits(metrics.Temperature, PlausibilityLimits{Min: -60, Max: 150, MaxRate: 1}).
+			WithLimits(metrics.Humidity, PlausibilityLimits{Min: 0, Max: 100, MaxRate: 1})
+		start = time.Now()
+		accepted = make([]string, 0)
+	)
+
+	for i, readings := range []map[models.Metric][]sensor.ReadingResult{
+		{
+			metrics.Temperature: {
+				{Source: "HDC1080@1/0x40", Value: 20, Timestamp: start},
+				{Source: "BMP280@1/0x76", Value: 30, Timestamp: start},
+			},
+			metrics.Humidity: {{Source: "HDC1080@1/0x40", Value: 40, Timestamp: start}},
+		},
+		{
+			// Spike of one sensor neither affects readings of another one, nor its own readings of other metrics:
+			metrics.Temperature: {
+				{Source: "HDC1080@1/0x40", Value: 50, Timestamp: start.Add(time.Second)},
+				{Source: "BMP280@1/0x76", Value: 30.5, Timestamp: start.Add(time.Second)},
+			},
+			metrics.Humidity: {{Source: "HDC1080@1/0x40", Value: 40.5, Timestamp: start.Add(time.Second)}},
+		},
+	} {
+		for metric, results := range p.Filter(readings) {
+			for _, result := range results {
+				accepted = append(accepted, fmt.Sprintf("%d:%s:%s", i, result.Source, metric))
+			}
+		}
+	}
+
+	sort.Strings(accepted)
+
+	if expected := []string{
+		"0:BMP280@1/0x76:temp", "0:HDC1080@1/0x40:hdt", "0:HDC1080@1/0x40:temp",
+		"1:BMP280@1/0x76:temp", "1:HDC1080@1/0x40:hdt",
+	}; fmt.Sprint(accepted) != fmt.Sprint(expected) {
+		t.Errorf("expected all readings but the spike to be accepted, got %v", accepted)
+	}
+
+	if rejected := p.Rejected(); rejected["HDC1080@1/0x40"] != 1 || rejected["BMP280@1/0x76"] != 0 {
+		t.Errorf("expected only spiking sensor to have rejected reading, got %v", rejected)
+	}
+}
@@ -0,0 +1,362 @@
+package engine
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// Available policies for handling requests, which are pushed to the full queue.
+const (
+	// DropNewestPolicy rejects newly pushed request.
+	DropNewestPolicy = "drop_newest"
+	// DropOldestPolicy evicts the oldest queued request of the same or lower priority.
+	DropOldestPolicy = "drop_oldest"
+	// MergePolicy merges newly pushed request into queued one with overlapping metrics,
+	// or falls back to DropOldestPolicy if there isn't such.
+	MergePolicy = "merge"
+)
+
+// Requests priority classes.
+const (
+	// onetimePriority is assigned to one-time requests, which are handled ahead of periodic ones.
+	onetimePriority = iota
+	// periodicPriority is assigned to requests of the periodic receivers.
+	periodicPriority
+	priorityClasses
+)
+
+var (
+	// ErrQueueFull is returned when request can't be queued due to lack of space.
+	ErrQueueFull = errors.New("requests queue is full")
+	// ErrQueueClosed is returned when request is pushed after the engine has been closed.
+	ErrQueueClosed = errors.New("requests queue is closed")
+)
+
+type (
+	// requestQueue defines bounded prioritized queue of the sensor reading requests.
+	requestQueue struct {
+		mutex    sync.Mutex
+		classes  [priorityClasses][]request
+		capacity int
+		policy   string
+		signal   chan struct{}
+		closed   bool
+
+		dropped       uint64
+		merged        uint64
+		highWatermark uint64
+	}
+
+	// QueueStats defines snapshot of the requests queue metrics.
+	QueueStats struct {
+		// Onetime is a number of queued one-time requests.
+		Onetime int
+		// Periodic is a number of queued periodic requests.
+		Periodic int
+		// Capacity is a maximum number of requests queue can hold.
+		Capacity int
+		// HighWatermark is a maximum observed queue depth.
+		HighWatermark uint64
+		// Dropped is a number of requests dropped due to queue being full.
+		Dropped uint64
+		// Merged is a number of requests merged into already queued ones due to queue being full.
+		Merged uint64
+		// ActiveHarvests is a number of currently performed sensors harvests.
+		ActiveHarvests int
+	}
+)
+
+// newRequestQueue constructs new requestQueue instance.
+func newRequestQueue(capacity int, policy string) *requestQueue {
+	if capacity <= 0 {
+		capacity = 1
+	}
+
+	return &requestQueue{
+		capacity: capacity,
+		policy:   policy,
+		signal:   make(chan struct{}, 1),
+	}
+}
+
+// push enqueues `req` according to its priority, applying queue policy if there is no space left.
+func (q *requestQueue) push(req request) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed {
+		return ErrQueueClosed
+	}
+
+	var (
+		class = priorityOf(req)
+	)
+
+	if req.Queued.IsZero() {
+		req.Queued = time.Now()
+	}
+
+	if q.depth() >= q.capacity {
+		switch q.policy {
+		case MergePolicy:
+			if q.mergeInto(req) {
+				atomic.AddUint64(&q.merged, 1)
+				return nil
+			}
+			fallthrough
+		case DropOldestPolicy:
+			evicted, ok := q.evictOldest(class)
+			atomic.AddUint64(&q.dropped, 1)
+
+			if !ok {
+				return ErrQueueFull
+			}
+
+			// Evicted request receivers are released with empty results, so that they won't wait for it forever:
+			shared.Logger.Warning(errors.Wrapf(ErrQueueFull, "request #%d for %v is evicted", evicted.ID, evicted.Metrics))
+			if evicted.Handler != nil {
+				go evicted.Handler(ReadingResults{})
+			}
+		default:
+			atomic.AddUint64(&q.dropped, 1)
+			return ErrQueueFull
+		}
+	}
+
+	q.classes[class] = append(q.classes[class], req)
+
+	if depth := uint64(q.depth()); depth > atomic.LoadUint64(&q.highWatermark) {
+		atomic.StoreUint64(&q.highWatermark, depth)
+	}
+
+	select {
+	case q.signal <- struct{}{}:
+	default:
+	}
+
+	return nil
+}
+
+// popBatch dequeues the request with the highest priority along with all queued requests,
+// which are connected with it by overlapping metrics, so that they all could be handled by single harvest.
+func (q *requestQueue) popBatch() (batch, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	var (
+		b batch
+		metrics = make(map[models.Metric]bool)
+	)
+
+	for class := range q.classes {
+		if len(q.classes[class]) != 0 {
+			b = append(b, q.classes[class][0])
+			q.classes[class] = q.classes[class][1:]
+			break
+		}
+	}
+
+	if len(b) == 0 {
+		return nil, false
+	}
+
+	for _, metric := range b[0].Metrics {
+		metrics[metric] = true
+	}
+
+	// Keep collecting overlapping requests until there are no more left,
+	// since each added request may extend the set of metrics:
+	for found := true; found; {
+		found = false
+
+		for class := range q.classes {
+			var (
+				left = q.classes[class][:0]
+			)
+
+			for _, req := range q.classes[class] {
+				if overlaps(req.Metrics, metrics) {
+					for _, metric := range req.Metrics {
+						metrics[metric] = true
+					}
+
+					b = append(b, req)
+					found = true
+					continue
+				}
+
+				left = append(left, req)
+			}
+
+			q.classes[class] = left
+		}
+	}
+
+	return b, true
+}
+
+// empty determines whether there are no queued requests.
+func (q *requestQueue) empty() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return q.depth() == 0
+}
+
+// close stops queue from accepting new requests and discards queued ones.
+func (q *requestQueue) close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	q.closed = true
+	q.classes = [priorityClasses][]request{}
+}
+
+// stats returns snapshot of the queue metrics.
+func (q *requestQueue) stats() QueueStats {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	return QueueStats{
+		Onetime:       len(q.classes[onetimePriority]),
+		Periodic:      len(q.classes[periodicPriority]),
+		Capacity:      q.capacity,
+		HighWatermark: atomic.LoadUint64(&q.highWatermark),
+		Dropped:       atomic.LoadUint64(&q.dropped),
+		Merged:        atomic.LoadUint64(&q.merged),
+	}
+}
+
+func (q *requestQueue) depth() (depth int) {
+	for class := range q.classes {
+		depth += len(q.classes[class])
+	}
+
+	return
+}
+
+// mergeInto tries to merge `req` into queued request with overlapping metrics,
+// so that both receivers will be handled by it.
+//
+// If the queued request is already due for the same receiver (e.g. its previous tick is still pending),
+// `req` is simply absorbed by it, so that receiver won't be handled twice.
+//
+// Merged request is moved to the higher priority class of the two,
+// so that urgent request won't wait behind the periodic ones it was merged into.
+func (q *requestQueue) mergeInto(req request) bool {
+	var (
+		metrics = make(map[models.Metric]bool)
+	)
+
+	for _, metric := range req.Metrics {
+		metrics[metric] = true
+	}
+
+	for class := range q.classes {
+		for i, queued := range q.classes[class] {
+			if !overlaps(queued.Metrics, metrics) {
+				continue
+			}
+
+			if !includesReceivers(queued, req) {
+				queued = mergeRequests(queued, req)
+			}
+
+			if target := priorityOf(req); target < class {
+				q.classes[class] = append(q.classes[class][:i], q.classes[class][i + 1:]...)
+				q.classes[target] = append(q.classes[target], queued)
+			} else {
+				q.classes[class][i] = queued
+			}
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// evictOldest removes and returns the oldest queued request with the same or lower priority than `class`.
+func (q *requestQueue) evictOldest(class int) (request, bool) {
+	for c := priorityClasses - 1; c >= class; c-- {
+		if len(q.classes[c]) != 0 {
+			evicted := q.classes[c][0]
+			q.classes[c] = q.classes[c][1:]
+			return evicted, true
+		}
+	}
+
+	return request{}, false
+}
+
+// mergeRequests combines requests `a` and `b` into single one,
+// which reads union of their metrics and passes to each receiver only the requested ones.
+func mergeRequests(a, b request) request {
+	var (
+		merged = batch{a, b}
+	)
+
+	return request{
+		ID:        a.ID,
+		Receivers: append(append(make([]uint64, 0, len(a.Receivers) + len(b.Receivers)), a.Receivers...), b.Receivers...),
+		Metrics:   merged.metrics(),
+		Interval:  merged.interval(),
+		Queued:    earliest(a.Queued, b.Queued),
+		Handler: func(results ReadingResults) {
+			a.Handler(results.Filter(a.Metrics...))
+			b.Handler(results.Filter(b.Metrics...))
+		},
+	}
+}
+
+func earliest(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+
+	return a
+}
+
+// includesReceivers determines whether all receivers of `b` are already handled by `a`.
+func includesReceivers(a, b request) bool {
+	for _, rb := range b.Receivers {
+		var found bool
+
+		for _, ra := range a.Receivers {
+			if ra == rb {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+func priorityOf(req request) int {
+	if req.Interval == 0 {
+		return onetimePriority
+	}
+
+	return periodicPriority
+}
+
+func overlaps(metrics []models.Metric, set map[models.Metric]bool) bool {
+	for _, metric := range metrics {
+		if set[metric] {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,242 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+func TestRequestQueuePolicies(t *testing.T) {
+	shared.Logger = logging.MustGetLogger("test")
+
+	for _, tc := range []struct {
+		name     string
+		policy   string
+		queued   time.Duration
+		pushed   request
+		err      error
+		expected string
+		released []uint64
+		dropped  uint64
+		merged   uint64
+	}{
+		{"drop newest", DropNewestPolicy, time.Second,
+			testRequest(3, 0, metrics.Luminosity), ErrQueueFull, "[[] [1 2]]", nil, 1, 0},
+		{"drop oldest", DropOldestPolicy, time.Second,
+			testRequest(3, time.Second, metrics.Luminosity), nil, "[[] [2 3]]", []uint64{1}, 1, 0},
+		{"drop oldest of lower priority", DropOldestPolicy, time.Second,
+			testRequest(3, 0, metrics.Luminosity), nil, "[[3] [2]]", []uint64{1}, 1, 0},
+		{"drop oldest keeps higher priority", DropOldestPolicy, 0,
+			testRequest(3, time.Second, metrics.Luminosity), ErrQueueFull, "[[1 2] []]", nil, 1, 0},
+		{"merge", MergePolicy, time.Second,
+			testRequest(3, time.Second, metrics.Humidity, metrics.Pressure), nil, "[[] [1 2]]", nil, 0, 1},
+		{"merge falls back to drop oldest", MergePolicy, time.Second,
+			testRequest(3, time.Second, metrics.Luminosity), nil, "[[] [2 3]]", []uint64{1}, 1, 0},
+		{"unknown policy", "", time.Second,
+			testRequest(3, time.Second, metrics.Humidity), ErrQueueFull, "[[] [1 2]]", nil, 1, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				q = newRequestQueue(2, tc.policy)
+				released = make(chan uint64, 3)
+			)
+
+			for _, req := range []request{
+				testRequest(1, tc.queued, metrics.Temperature),
+				testRequest(2, tc.queued, metrics.Humidity),
+				tc.pushed,
+			} {
+				id := req.ID
+				req.Handler = func(results ReadingResults) {
+					if len(results) == 0 {
+						released <- id
+					}
+				}
+
+				if err := q.push(req); req.ID == tc.pushed.ID && err != tc.err {
+					t.Fatalf("expected push error %v, got %v", tc.err, err)
+				} else if req.ID != tc.pushed.ID && err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if queued := fmt.Sprint(queuedIDs(q)); queued != tc.expected {
+				t.Errorf("expected queued requests %s, got %s", tc.expected, queued)
+			}
+
+			for _, id := range tc.released {
+				select {
+				case evicted := <- released:
+					if evicted != id {
+						t.Errorf("expected evicted request #%d to be released, got #%d", id, evicted)
+					}
+				case <- time.After(time.Second):
+					t.Fatalf("expected evicted request #%d to be released with empty results", id)
+				}
+			}
+
+			if stats := q.stats(); stats.Dropped != tc.dropped || stats.Merged != tc.merged {
+				t.Errorf("expected %d dropped and %d merged, got %d and %d",
+					tc.dropped, tc.merged, stats.Dropped, stats.Merged)
+			}
+		})
+	}
+}
+
+func TestRequestQueuePopBatch(t *testing.T) {
+	var (
+		q = newRequestQueue(10, DropNewestPolicy)
+	)
+
+	for _, req := range []request{
+		testRequest(1, time.Second, metrics.Luminosity),
+		testRequest(2, time.Second, metrics.Humidity),
+		testRequest(3, time.Second, metrics.Pressure, metrics.Humidity),
+		testRequest(4, 0, metrics.Temperature),
+		testRequest(5, time.Second, metrics.Temperature, metrics.Pressure),
+		testRequest(6, time.Second, metrics.Acceleration),
+	} {
+		if err := q.push(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// One-time request is popped first, collecting requests connected to it through metrics of each other:
+	for _, expected := range []string{"requests #4, #5, #3, #2", "request #1", "request #6"} {
+		b, ok := q.popBatch()
+		if !ok {
+			t.Fatalf("expected batch of %s to be popped", expected)
+		}
+
+		if b.String() != expected {
+			t.Errorf("expected batch of %s, got %s", expected, b)
+		}
+	}
+
+	if b, ok := q.popBatch(); ok || !q.empty() {
+		t.Errorf("expected queue to be empty, got %s", b)
+	}
+}
+
+func TestRequestQueueMergeInto(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		queued    request
+		pushed    request
+		receivers string
+		metrics   string
+		onetime   int
+		delivered string
+	}{
+		{"one-time promotes periodic", testRequest(1, time.Second, metrics.Temperature),
+			testRequest(2, 0, metrics.Temperature, metrics.Humidity), "[1 2]", "[temp hdt]", 1,
+			"map[1:[temp] 2:[hdt temp]]"},
+		{"periodic keeps one-time", testRequest(1, 0, metrics.Temperature),
+			testRequest(2, time.Second, metrics.Temperature), "[1 2]", "[temp]", 1,
+			"map[1:[temp] 2:[temp]]"},
+		{"periodic stays periodic", testRequest(1, time.Second, metrics.Temperature),
+			testRequest(2, time.Minute, metrics.Temperature), "[1 2]", "[temp]", 0,
+			"map[1:[temp] 2:[temp]]"},
+		{"pending receiver absorbed", testRequest(1, time.Second, metrics.Temperature),
+			testRequest(2, time.Second, metrics.Temperature, metrics.Humidity).withReceivers(1), "[1]", "[temp]", 0,
+			"map[1:[temp]]"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				q = newRequestQueue(1, MergePolicy)
+				delivered = make(map[uint64][]models.Metric)
+			)
+
+			for _, req := range []request{tc.queued, tc.pushed} {
+				id := req.ID
+				req.Handler = func(results ReadingResults) {
+					for metric := range results {
+						delivered[id] = append(delivered[id], metric)
+					}
+				}
+
+				if err := q.push(req); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if stats := q.stats(); stats.Onetime != tc.onetime || stats.Onetime + stats.Periodic != 1 || stats.Merged != 1 {
+				t.Fatalf("expected requests to be merged into %d one-time, got %+v", tc.onetime, stats)
+			}
+
+			b, _ := q.popBatch()
+
+			if receivers := fmt.Sprint(b[0].Receivers); receivers != tc.receivers {
+				t.Errorf("expected merged request receivers %s, got %s", tc.receivers, receivers)
+			}
+
+			if merged := fmt.Sprint(b[0].Metrics); merged != tc.metrics {
+				t.Errorf("expected merged request metrics %s, got %s", tc.metrics, merged)
+			}
+
+			// Merged request passes each receiver only the metrics it has requested:
+			b[0].Handler(ReadingResults{
+				metrics.Temperature: sensor.ReadingResult{Value: 21},
+				metrics.Humidity:    sensor.ReadingResult{Value: 40},
+			}.Filter(b[0].Metrics...))
+
+			var (
+				received = make(map[uint64]string)
+			)
+
+			for id, metrics := range delivered {
+				received[id] = sortedMetrics(metrics)
+			}
+
+			if fmt.Sprint(received) != tc.delivered {
+				t.Errorf("expected receivers to get %s, got %v", tc.delivered, received)
+			}
+		})
+	}
+}
+
+// testRequest constructs request with given `id`, `interval` and `metrics` for its own receiver.
+func testRequest(id uint64, interval time.Duration, metrics ...models.Metric) request {
+	return request{
+		ID:        id,
+		Receivers: []uint64{id},
+		Metrics:   metrics,
+		Interval:  interval,
+	}
+}
+
+func (r request) withReceivers(receivers ...uint64) request {
+	r.Receivers = receivers
+	return r
+}
+
+// queuedIDs returns IDs of the queued requests per priority class.
+func queuedIDs(q *requestQueue) (ids [priorityClasses][]uint64) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for class := range q.classes {
+		ids[class] = []uint64{}
+
+		for _, req := range q.classes[class] {
+			ids[class] = append(ids[class], req.ID)
+		}
+	}
+
+	return
+}
+
+func sortedMetrics(metrics []models.Metric) string {
+	sorted := append([]models.Metric(nil), metrics...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return fmt.Sprint(sorted)
+}
@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"github.com/timoth-y/chainmetric-core/models"
+)
+
+// Filter returns ReadingResults containing only values for given `metrics`.
+func (rr ReadingResults) Filter(metrics ...models.Metric) ReadingResults {
+	var (
+		results = make(ReadingResults, len(metrics))
+	)
+
+	for _, metric := range metrics {
+		if result, ok := rr[metric]; ok {
+			results[metric] = result
+		}
+	}
+
+	return results
+}
+
+// Values returns bare values of the ReadingResults, stripped from readings metadata.
+func (rr ReadingResults) Values() map[models.Metric]float64 {
+	var (
+		values = make(map[models.Metric]float64, len(rr))
+	)
+
+	for metric, result := range rr {
+		values[metric] = result.Value
+	}
+
+	return values
+}
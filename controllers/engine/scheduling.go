@@ -0,0 +1,186 @@
+package engine
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// Available scheduling modes of the periodic receivers.
+const (
+	// RelativeScheduling sends next request after interval passes since the previous one has been sent.
+	RelativeScheduling = "relative"
+	// AlignedScheduling sends requests on wall-clock boundaries, which are multiplies of the interval.
+	AlignedScheduling = "aligned"
+)
+
+// Available policies for handling ticks missed by aligned schedule.
+const (
+	// SkipMissedTicks drops all missed ticks and waits for the next boundary.
+	SkipMissedTicks = "skip"
+	// CatchUpMissedTicks sends request for each missed tick one after another.
+	CatchUpMissedTicks = "catch_up"
+	// MergeMissedTicks sends single request for all missed ticks at once.
+	MergeMissedTicks = "merge"
+)
+
+var (
+	// jitterRand is seeded once per device, since the global source is unseeded by default,
+	// which would make all devices draw the same jitter sequence.
+	jitterRand  = rand.New(rand.NewSource(jitterSeed()))
+	jitterMutex = sync.Mutex{}
+)
+
+// alignedTicker defines drift-free scheduler, which ticks on the wall-clock boundaries of the interval
+// with optional random jitter to avoid simultaneous requests from different devices.
+type alignedTicker struct {
+	interval time.Duration
+	jitter   time.Duration
+	policy   string
+	next     time.Time
+}
+
+// newAlignedTicker constructs new alignedTicker instance with first tick on the closest upcoming boundary.
+func newAlignedTicker(interval, jitter time.Duration, policy string) *alignedTicker {
+	return &alignedTicker{
+		interval: interval,
+		jitter:   jitter,
+		policy:   policy,
+		next:     time.Now().Truncate(interval).Add(interval),
+	}
+}
+
+// wait blocks until the next tick is due, or the `ctx` is done, which will be reported by returning false.
+func (t *alignedTicker) wait(ctx context.Context) bool {
+	var (
+		now = time.Now()
+	)
+
+	if missed := t.missed(now); missed > 0 {
+		switch t.policy {
+		case CatchUpMissedTicks:
+			// Fire right away, the rest of the missed ticks will follow with next calls:
+			t.next = t.next.Add(t.interval)
+			return ctx.Err() == nil
+		case MergeMissedTicks:
+			shared.Logger.Debugf("Aligned schedule: %d missed ticks are merged into single one", missed)
+			t.next = now.Truncate(t.interval).Add(t.interval)
+			return ctx.Err() == nil
+		default:
+			shared.Logger.Debugf("Aligned schedule: %d missed ticks are skipped", missed)
+			t.next = now.Truncate(t.interval).Add(t.interval)
+		}
+	}
+
+	var (
+		fireAt = t.next
+	)
+
+	if t.jitter > 0 {
+		fireAt = fireAt.Add(randomJitter(t.jitter))
+	}
+
+	select {
+	case <- time.After(time.Until(fireAt)):
+		t.next = t.next.Add(t.interval)
+		return true
+	case <- ctx.Done():
+		return false
+	}
+}
+
+// missed counts boundaries which have already passed by the moment of `now`, but weren't ticked on.
+func (t *alignedTicker) missed(now time.Time) int {
+	if now.Before(t.next.Add(t.jitter)) {
+		return 0
+	}
+
+	return int(now.Sub(t.next) / t.interval) + 1
+}
+
+// randomJitter returns random duration in [0, max) range.
+func randomJitter(max time.Duration) time.Duration {
+	jitterMutex.Lock()
+	defer jitterMutex.Unlock()
+
+	return time.Duration(jitterRand.Int63n(int64(max)))
+}
+
+// jitterSeed derives jitter random source seed from the current time and the device hostname,
+// so that devices booted simultaneously would still differ.
+func jitterSeed() int64 {
+	var (
+		seed = time.Now().UnixNano()
+	)
+
+	if hostname, err := os.Hostname(); err == nil {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(hostname))
+		seed ^= int64(h.Sum64())
+	}
+
+	return seed
+}
+
+// scheduleRelative sends request for `metrics` every `interval` after the previous one has been sent.
+func (r *SensorsReader) scheduleRelative(
+	ctx context.Context,
+	receiver uint64,
+	handler ReceiverFunc,
+	interval time.Duration,
+	metrics []models.Metric,
+) {
+	for {
+		if !r.enqueue(request{
+			ID:        r.nextRequestID(),
+			Receivers: []uint64{receiver},
+			Metrics:   metrics,
+			Handler:   handler,
+			Interval:  interval,
+		}) {
+			return
+		}
+
+		select {
+		case <- ctx.Done():
+			return
+		case <- time.After(interval):
+		}
+	}
+}
+
+// scheduleAligned sends request for `metrics` on each wall-clock boundary of the `interval`.
+func (r *SensorsReader) scheduleAligned(
+	ctx context.Context,
+	receiver uint64,
+	handler ReceiverFunc,
+	interval time.Duration,
+	metrics []models.Metric,
+) {
+	var (
+		ticker = newAlignedTicker(interval,
+			viper.GetDuration("engine.scheduling.jitter"),
+			viper.GetString("engine.scheduling.missed_tick_policy"),
+		)
+	)
+
+	for ticker.wait(ctx) {
+		if !r.enqueue(request{
+			ID:        r.nextRequestID(),
+			Receivers: []uint64{receiver},
+			Metrics:   metrics,
+			Handler:   handler,
+			Interval:  interval,
+		}) {
+			return
+		}
+	}
+}
@@ -2,10 +2,9 @@ package engine
 
 import (
 	"context"
-	"fmt"
-	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
@@ -13,123 +12,272 @@ import (
 	"github.com/timoth-y/chainmetric-core/models"
 
 	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/model/config"
+	"github.com/timoth-y/chainmetric-iot/model/events"
 	"github.com/timoth-y/chainmetric-iot/shared"
+	"github.com/timoth-y/go-eventdriver"
 )
 
 type (
 	// SensorsReader defines operational structure of the sensors reading engine.
 	SensorsReader struct {
 		once          *sync.Once
-		sensors       sensor.SensorsRegister
-		requests      chan request
-		standbyTimers map[sensor.Sensor]*time.Timer
+		sensors       *sensor.Registry
+		unsubscribe   func()
+		queue         *requestQueue
+		harvests      chan struct{}
 		active        bool
 		cancel        context.CancelFunc
+		aggregator    *Aggregator
+		coalescing    *coalescingCounters
+		health        *healthTracker
+		plausibility  *Plausibility
+		calibrations  sensor.CalibrationProvider
+		recorder      *sensor.Recorder
+		warmUpPolicy  string
+		stats         *statsCollector
+		throttle      *throttle
+		lastRequestID uint64
+		lastReceiverID uint64
 	}
 
-	// ReadingResults defines map of values collected from sensor.Sensor for requested models.Metrics.
-	ReadingResults map[models.Metric] float64
+	// ReadingResults defines map of aggregated readings collected from sensor.Sensor for requested models.Metrics.
+	ReadingResults map[models.Metric] sensor.ReadingResult
 
 	// ReceiverFunc defines signature for sensor readings results receiver handler function.
 	ReceiverFunc func(ReadingResults)
 
 	// request stores data for the sensor readings request payload.
 	request struct {
-		Context context.Context
-		Metrics []models.Metric
-		Handler ReceiverFunc
+		ID        uint64
+		Receivers []uint64
+		Context   context.Context
+		Metrics   []models.Metric
+		Handler   ReceiverFunc
+		Interval  time.Duration
+		Queued    time.Time
 	}
 )
 
 // NewSensorsReader constructs new SensorsReader instance.
 func NewSensorsReader() *SensorsReader {
-	return &SensorsReader{
+	var (
+		aggregationConfig config.AggregationConfig
+		plausibilityConfig config.PlausibilityConfig
+	)
+
+	// Aggregation and plausibility configs contain maps,
+	// which would be shadowed by env bindings of shared.UnmarshalFromConfig, so they are decoded directly:
+	if err := viper.UnmarshalKey("engine.aggregation", &aggregationConfig); err != nil {
+		shared.Logger.Error(errors.Wrap(err, "failed to parse readings aggregation config"))
+	}
+
+	if err := viper.UnmarshalKey("engine.plausibility", &plausibilityConfig); err != nil {
+		shared.Logger.Error(errors.Wrap(err, "failed to parse readings plausibility config"))
+	}
+
+	aggregator, err := NewAggregatorFromConfig(aggregationConfig)
+	if err != nil {
+		shared.Logger.Error(errors.Wrap(err, "failed to configure readings aggregation, defaults are used instead"))
+	}
+
+	var r = &SensorsReader{
 		once:          &sync.Once{},
-		sensors:       make(map[string]sensor.Sensor),
-		requests:      make(chan request),
-		standbyTimers: make(map[sensor.Sensor]*time.Timer),
+		queue:         newRequestQueue(
+			viper.GetInt("engine.queue.capacity"),
+			viper.GetString("engine.queue.full_policy"),
+		),
+		harvests:      make(chan struct{}, maxConcurrentHarvests()),
+		aggregator:    aggregator,
+		coalescing:    &coalescingCounters{},
+		health:        newHealthTracker(
+			viper.GetInt("engine.health.failure_threshold"),
+			viper.GetDuration("engine.health.quarantine_backoff"),
+			viper.GetDuration("engine.health.max_quarantine_backoff"),
+		),
+		plausibility:  NewPlausibilityFromConfig(plausibilityConfig),
+		warmUpPolicy:  viper.GetString("engine.warm_up.policy"),
+		stats:         newStatsCollector(),
+		throttle:      newThrottle(),
 	}
+
+	r.SetRegistry(sensor.NewRegistry(viper.GetDuration("engine.sensor_sleep_standby_timeout")))
+
+	return r
 }
+
+// SetRegistry sets sensors `registry`, which can be shared with other components,
+// such as device.Device and its hotswap detector, instead of the SensorsReader's own one.
+func (r *SensorsReader) SetRegistry(registry *sensor.Registry) {
+	if r.unsubscribe != nil {
+		r.unsubscribe()
+	}
+
+	r.sensors = registry
+	r.unsubscribe = registry.Subscribe(func(event sensor.RegistryEvent) {
+		for _, id := range event.Removed {
+			r.health.forget(id)
+			r.throttle.forget(id)
+			r.stats.forget(id)
+		}
+
+		for _, id := range event.Standby {
+			r.stats.standbyClosed(id)
+		}
+	})
+}
+// SetCalibrations sets `provider` of the per-sensor calibration, which will be applied to sensors readings.
+func (r *SensorsReader) SetCalibrations(provider sensor.CalibrationProvider) {
+	r.calibrations = provider
+}
+
+// SetRecorder sets `recorder`, which will be writing every harvested sensor reading into the recording file.
+func (r *SensorsReader) SetRecorder(recorder *sensor.Recorder) {
+	r.recorder = recorder
+}
+
 // RegisteredSensors returns map with sensors registered on the engine.SensorsReader.
 func (r *SensorsReader) RegisteredSensors() sensor.SensorsRegister {
-	return r.sensors
+	return r.sensors.Snapshot()
 }
 
 // RegisterSensors adds given `sensors` on the SensorsReader sensors pool.
 func (r *SensorsReader) RegisterSensors(sensors ...sensor.Sensor) {
-	for i, s := range sensors {
-		r.sensors[s.ID()] = sensors[i]
-	}
+	r.sensors.Add(sensors...)
 }
 
 // UnregisterSensors removes sensor by given `id` from the SensorsReader sensors pool.
 func (r *SensorsReader) UnregisterSensors(ids ...string) {
-	for _, id := range ids{
-		if s, ok := r.sensors[id]; ok {
-			if s.Active() {
-				if err := s.Close(); err != nil {
-					shared.Logger.Error(errors.Wrapf(err, "failed to close connection to '%s' sensor", s.ID()))
-				}
-			}
-			delete(r.sensors, id)
-		}
-	}
+	r.sensors.Remove(ids...)
 }
 
 // SubscribeReceiver creates receiver subscription routine with given `handler`
 // and starts creating sensor reading requests every given `interval`.
+//
+// Depending on `engine.scheduling.mode` requests are either sent relatively to the previous one,
+// or aligned with wall-clock boundaries of the `interval` (see AlignedScheduling).
 func (r *SensorsReader) SubscribeReceiver(
 	ctx context.Context,
 	handler ReceiverFunc,
 	interval time.Duration,
 	metrics ...models.Metric,
 ) context.CancelFunc {
+	var (
+		receiver = r.nextReceiverID()
+	)
+
 	ctx, cancel := context.WithCancel(ctx)
-	go func(ctx context.Context) {
-		LOOP: for {
-			r.requests <- request{
-				Metrics: metrics,
-				Handler: handler,
-			}
 
-			select {
-			case <- ctx.Done():
-				break LOOP
-			default:
-				time.Sleep(interval)
-			}
-		}
-	}(ctx)
+	switch viper.GetString("engine.scheduling.mode") {
+	case AlignedScheduling:
+		go r.scheduleAligned(ctx, receiver, handler, interval, metrics)
+	default:
+		go r.scheduleRelative(ctx, receiver, handler, interval, metrics)
+	}
 
 	return cancel
 }
 
 // SendRequest creates single request for sensor readings that will be handled with given `handler`.
+//
+// One-time requests have priority over the periodic ones, thus will be handled ahead of them.
 func (r *SensorsReader) SendRequest(handler ReceiverFunc, metrics ...models.Metric) {
-	r.requests <- request{
-		Metrics: metrics,
-		Handler: handler,
+	if err := r.queue.push(request{
+		ID:        r.nextRequestID(),
+		Receivers: []uint64{r.nextReceiverID()},
+		Metrics:   metrics,
+		Handler:   handler,
+	}); err != nil {
+		shared.Logger.Warning(errors.Wrapf(err, "one-time request for %v is discarded", metrics))
 	}
 }
 
+// CoalescingStats returns counters showing how efficiently overlapping requests are being coalesced.
+func (r *SensorsReader) CoalescingStats() CoalescingStats {
+	return r.coalescing.snapshot()
+}
+
+// SensorsHealth returns health state of the sensors, which have failed at least once.
+func (r *SensorsReader) SensorsHealth() map[string]SensorHealth {
+	return r.health.snapshot()
+}
+
+// RejectedReadings returns number of implausible readings rejected per sensor.
+func (r *SensorsReader) RejectedReadings() map[string]uint64 {
+	return r.plausibility.Rejected()
+}
+
+// Stats returns snapshot of the engine performance statistics.
+func (r *SensorsReader) Stats() EngineStats {
+	stats := r.stats.snapshot()
+	stats.Queue = r.QueueStats()
+	stats.Coalescing = r.CoalescingStats()
+	stats.Health = r.SensorsHealth()
+	stats.Rejected = r.RejectedReadings()
+
+	return stats
+}
+
 // Run starts working on the on the received requests by reading sensors data.
 func (r *SensorsReader) Run(ctx context.Context) {
 	ctx, r.cancel = context.WithCancel(ctx)
 	r.active = true
 
 	go r.once.Do(func() {
+		var (
+			window = viper.GetDuration("engine.coalesce_window")
+		)
+
 		for {
 			select {
-			case request := <- r.requests:
-				go r.handleRequest(ctx, request)
+			case <- r.queue.signal:
 			case <- ctx.Done():
+				r.queue.close()
 				shared.Logger.Debug("Sensors reader engine routine ended")
 				return
 			}
+
+			// Requests received within the coalescing window are considered to be due at the same time:
+			if window > 0 {
+				select {
+				case <- time.After(window):
+				case <- ctx.Done():
+					continue
+				}
+			}
+
+		DISPATCH:
+			for !r.queue.empty() {
+				// Wait for the free harvest slot, while queue applies backpressure to the new requests:
+				select {
+				case r.harvests <- struct{}{}:
+				case <- ctx.Done():
+					break DISPATCH
+				}
+
+				b, ok := r.queue.popBatch()
+				if !ok {
+					<- r.harvests
+					break
+				}
+
+				go func(b batch) {
+					defer func() { <- r.harvests }()
+					r.handleBatch(ctx, b)
+				}(b)
+			}
 		}
 	})
 }
 
+// QueueStats returns snapshot of the requests queue metrics.
+func (r *SensorsReader) QueueStats() QueueStats {
+	stats := r.queue.stats()
+	stats.ActiveHarvests = len(r.harvests)
+
+	return stats
+}
+
 // Active determines whether the SensorReader instance is running.
 func (r *SensorsReader) Active() bool {
 	return r.active
@@ -139,116 +287,205 @@ func (r *SensorsReader) Active() bool {
 func (r *SensorsReader) Close() {
 	r.active = false
 	r.cancel()
-
-	for _, s := range r.sensors {
-		if s.Active() {
-			if err := s.Close(); err != nil {
-				shared.Logger.Error(errors.Wrapf(err, "failed to close connection to '%s' sensor", s.ID()))
-			}
-		}
-	}
+	r.sensors.Close()
 }
 
-func (r *SensorsReader) handleRequest(ctx context.Context, req request) {
+// handleBatch harvests sensors once for all requests in the coalesced batch
+// and fans reading results out to each request's receiver.
+func (r *SensorsReader) handleBatch(ctx context.Context, b batch) {
 	var (
 		waitGroup = &sync.WaitGroup{}
 		pipe = make(sensor.ReadingsPipe)
+		sensors = r.sensors.Snapshot()
+		virtuals = virtualsFor(sensors, b.metrics())
+		metrics = withInputs(b.metrics(), virtuals)
+		interval = b.interval()
+		timedOut = make([]string, 0)
+		sensorsRead = 0
+		mutex = &sync.Mutex{}
 	)
 
-	// Init channels in request results pipe:
-	for _, metric := range req.Metrics {
-		pipe[metric] = make(chan sensor.ReadingResult, 3)
+	for _, req := range b {
+		r.stats.queueWaited(req)
 	}
 
-	// Create single timeout context for all suitable for requested metrics sensors:
-	// TODO: each sensor may take different amount of time to be read,
-	//  thus some kind of deterministic timeout handling approach is required here.
-	//  Readings interval specified by receiver also should be taken in the account here.
-	ctx, cancel := context.WithTimeout(ctx, 3 * time.Second)
-	defer cancel()
+	// Init channels in request results pipe, including ones for inputs of the virtual sensors:
+	for _, metric := range metrics {
+		pipe[metric] = make(chan sensor.ReadingResult, len(sensors))
+	}
 
 	// Go through available sensors to check is there any compatible ones for requested metrics,
-	// and if so perform reading from them:
-	for _, sn := range r.sensors {
-		for _, metric := range req.Metrics {
+	// and if so perform reading from them, unless they are quarantined.
+	// Virtual sensors are harvested afterwards, once readings of the physical ones are available:
+	for _, sn := range sensors {
+		if _, ok := sn.(sensor.Virtual); ok {
+			continue
+		}
+
+		for _, metric := range metrics {
 			if suitable(sn, metric) {
+				var health = r.health.check(sn.ID())
+
+				if health == sensorQuarantined {
+					break
+				}
+
 				waitGroup.Add(1)
+				sensorsRead++
+
+				go func(sn sensor.Sensor, health int) {
+					defer waitGroup.Done()
+
+					// Quarantined sensor must pass re-probe before it could be read again:
+					if health == sensorProbeDue && !r.probeSensor(ctx, sn) {
+						return
+					}
+
+					// Sensor isn't polled more often than its declared minimal interval, latest readings are reused instead:
+					if r.throttle.reuse(sn, pipe) {
+						return
+					}
+
+					// Each sensor gets its own deadline based on declared reading duration
+					// and the interval of the receivers, which have made this requests:
+					var budget = readBudget(sn, interval)
+
+					sensorCtx, cancel := context.WithTimeout(ctx, budget)
+					defer cancel()
 
-				go func(sn sensor.Sensor) {
 					// Create new reading context for sensor and assign channels pipe,
 					// where reading results will be dumped into:
-					sensorCtx := sensor.NewReaderContext(ctx, sn)
-					sensorCtx.Pipe = pipe
+					readerCtx := sensor.NewReaderContext(sensorCtx, sn)
+					readerCtx.Pipe = pipe
+					readerCtx.Calibrations = r.calibrations
 
-					// First time use initialization along with stand by handling:
-					if err := r.initSensor(sn); err != nil {
-						sensorCtx.Error(err)
+					// First time use initialization, sensor is kept from standby until its harvest is over:
+					release, initialized, err := r.sensors.Acquire(sn)
+					if err == sensor.ErrSensorUnregistered {
+						return
+					} else if err != nil {
+						readerCtx.Error(err)
+						r.stats.failed(sn.ID())
+						r.trackHealth(ctx, sn, readerCtx)
 						return
 					}
 
-					r.readSensor(sensorCtx, sn, waitGroup)
-				}(sn)
+					if initialized {
+						r.stats.initialized(sn.ID())
+					}
+
+					// Sensors, which are still warming up, are either read provisionally or not at all:
+					if r.holdWarmingUp(sn, readerCtx) {
+						release()
+						return
+					}
+
+					var (
+						start = time.Now()
+						completed = r.readSensor(readerCtx, sn, release)
+					)
+
+					r.stats.harvested(sn.ID(), time.Since(start), !completed, readerCtx.Failed())
+
+					if !completed {
+						readerCtx.Error(errors.Errorf(
+							"sensor reading timeout: time exceeded %v budget for %s", budget, b,
+						))
+
+						mutex.Lock()
+						timedOut = append(timedOut, sn.ID())
+						mutex.Unlock()
+					}
+
+					r.trackHealth(ctx, sn, readerCtx)
+				}(sn, health)
 
 				break
 			}
 		}
 	}
 
+	r.coalescing.track(b, sensorsRead, r.countSuitable)
+
 	// Wait until all required sensors finish being read or timed out:
 	waitGroup.Wait()
 
-	// Finally, aggregate sensor reading results and handle them by passing to receiver:
-	results := aggregate(pipe)
-	req.Handler(results)
+	if len(timedOut) != 0 {
+		shared.Logger.Warningf("%s for %v: %d sensor(s) timed out: %s",
+			b, metrics, len(timedOut), strings.Join(timedOut, ", "),
+		)
+	}
 
-	return
-}
+	// Finally, record harvested readings if needed, reject implausible ones,
+	// aggregate the rest along with derived ones, and fan results out to receivers:
+	var (
+		readings = drainPipe(pipe)
+	)
 
-func suitable(sensor sensor.Sensor, metric models.Metric) bool {
-	for _, m := range sensor.Metrics() {
-		if metric == m {
-			return true
+	r.throttle.remember(readings)
+
+	if r.recorder != nil {
+		if err := r.recorder.Record(readings); err != nil {
+			shared.Logger.Error(errors.Wrap(err, "failed to record harvested readings"))
 		}
 	}
 
-	return false
+	results := r.aggregator.Aggregate(r.plausibility.Filter(readings))
+
+	if len(virtuals) != 0 {
+		r.derive(ctx, virtuals, results)
+	}
+
+	for _, req := range b {
+		r.stats.delivered(req)
+		go req.Handler(results.Filter(req.Metrics...))
+	}
 }
 
-func (r *SensorsReader) initSensor(sn sensor.Sensor) error {
-	var (
-		standby = viper.GetDuration("engine.sensor_sleep_standby_timeout")
-	)
+// countSuitable counts sensors suitable for reading at least one of the given `metrics`.
+func (r *SensorsReader) countSuitable(metrics []models.Metric) (count int) {
+	for _, sn := range r.sensors.Snapshot() {
+		if _, ok := sn.(sensor.Virtual); ok {
+			continue
+		}
 
-	if !sn.Active() {
-		if err := sn.Init(); err != nil {
-			return err
+		for _, metric := range metrics {
+			if suitable(sn, metric) {
+				count++
+				break
+			}
 		}
 	}
 
-	if timer, ok := r.standbyTimers[sn]; ok && timer != nil {
-		if !timer.Reset(standby) {
-			go handleStandby(timer, sn)
+	return
+}
+
+func suitable(sensor sensor.Sensor, metric models.Metric) bool {
+	for _, m := range sensor.Metrics() {
+		if metric == m {
+			return true
 		}
-	} else {
-		r.standbyTimers[sn] = time.NewTimer(standby)
-		go handleStandby(r.standbyTimers[sn], sn)
 	}
 
-	return nil
+	return false
 }
 
-func (r *SensorsReader) readSensor(ctx *sensor.Context, sn sensor.Sensor, wg *sync.WaitGroup) {
-	defer wg.Done()
-
+// readSensor harvests given `sn` sensor within the reading context
+// and reports whether it was able to do so before the deadline.
+// The `release` function is called once harvesting is over, even if it outlives the deadline.
+func (r *SensorsReader) readSensor(ctx *sensor.Context, sn sensor.Sensor, release func()) bool {
 	if !sn.Active() {
+		release()
 		ctx.Warning("attempt of reading from non-active sensor")
 
-		return
+		return true
 	}
 
-	done := make(chan bool)
+	done := make(chan bool, 1)
 
 	go func() {
+		defer release()
+
 		sn.Harvest(ctx)
 		done <- true
 	}()
@@ -257,69 +494,119 @@ func (r *SensorsReader) readSensor(ctx *sensor.Context, sn sensor.Sensor, wg *sy
 	case <- ctx.Done():
 		switch ctx.Err() {
 		case context.DeadlineExceeded:
-			ctx.Error(errors.New("sensor reading timeout: time exceeded"))
+			return false
 		case context.Canceled:
 			ctx.Info("sensor reading canceled by force")
 		}
-		return
+		return true
 	case <- done:
-		return
+		return true
 	}
 }
 
-func handleStandby(t *time.Timer, sn sensor.Sensor) {
-	<-t.C
-	shared.Execute(sn.Close, fmt.Sprintf("failed to close connection to '%s' sensor", sn.ID()))
-}
+// trackHealth registers outcome of the `sn` sensor reading and quarantines it once it keeps failing.
+func (r *SensorsReader) trackHealth(ctx context.Context, sn sensor.Sensor, readerCtx *sensor.Context) {
+	// Readings interrupted by engine shutdown aren't sensor's fault:
+	if ctx.Err() != nil {
+		return
+	}
 
-func aggregate(pipe sensor.ReadingsPipe) ReadingResults {
-	var (
-		results = make(ReadingResults)
+	if !readerCtx.Failed() {
+		r.health.success(sn.ID())
+		return
+	}
+
+	quarantined, failures, retryIn := r.health.failure(sn.ID(), readerCtx.LastError())
+	if !quarantined {
+		return
+	}
+
+	shared.Logger.Warningf("Sensor %s is quarantined after %d consecutive failures, will be re-probed in %v",
+		sensor.DisplayName(sn.ID()), failures, retryIn,
 	)
 
-	for metric, ch := range pipe {
-		readings := make([]sensor.ReadingResult, 0)
+	if err := r.sensors.Deactivate(sn); err != nil && err != sensor.ErrSensorUnregistered {
+		shared.Logger.Error(errors.Wrapf(err, "failed to close connection to '%s' sensor", sensor.DisplayName(sn.ID())))
+	}
 
-	LOOP: for {
-			select {
-			case reading := <- ch:
-				readings = append(readings, reading)
-			default:
-				break LOOP
-			}
-		}
+	eventdriver.EmitEvent(ctx, events.SensorDegraded, events.SensorDegradedPayload{
+		SensorID: sn.ID(),
+		Failures: failures,
+		Error:    readerCtx.LastError(),
+		RetryIn:  retryIn,
+	})
+}
 
-		if len(readings) != 0 {
-			results[metric] = selectResult(readings)
-		}
+// probeSensor tries to bring quarantined `sn` sensor back by verifying and re-initializing it.
+func (r *SensorsReader) probeSensor(ctx context.Context, sn sensor.Sensor) bool {
+	var err = r.sensors.Probe(sn)
+
+	downtime, retryIn := r.health.probed(sn.ID(), err)
+	if err != nil {
+		shared.Logger.Warningf("Sensor %s re-probe failed, will be retried in %v: %v", sensor.DisplayName(sn.ID()), retryIn, err)
+		return false
 	}
 
-	return results
+	r.stats.initialized(sn.ID())
+
+	shared.Logger.Infof("Sensor %s has recovered after %v in quarantine", sensor.DisplayName(sn.ID()), downtime.Round(time.Second))
+
+	eventdriver.EmitEvent(ctx, events.SensorRecovered, events.SensorRecoveredPayload{
+		SensorID: sn.ID(),
+		Downtime: downtime,
+	})
+
+	return true
 }
 
-func selectResult(results []sensor.ReadingResult) (result float64) {
+// readBudget determines how much time can be spent on reading `sn` sensor.
+//
+// It is based on the reading duration declared by sensor (see sensor.Timed) multiplied by safety margin,
+// but it won't exceed the `interval` of the periodic receiver, since by then the next request would be due.
+func readBudget(sn sensor.Sensor, interval time.Duration) time.Duration {
 	var (
-		getPrecision = func(v float64) int {
-			s := strconv.FormatFloat(v, 'f', -1, 64)
-			i := strings.IndexByte(s, '.')
-			if i > -1 {
-				return len(s) - i - 1
-			}
-			return 0
-		}
+		margin = viper.GetFloat64("engine.read_budget_margin")
+		minBudget = viper.GetDuration("engine.min_read_budget")
+		duration = sensor.ReadDuration(sn, viper.GetDuration("engine.default_read_duration"))
+		budget = time.Duration(float64(duration) * margin)
 	)
-	result = results[0].Value
-	lastPrecision := 0
 
-	for i := range results {
-		precision := getPrecision(results[i].Value)
+	if budget < minBudget {
+		budget = minBudget
+	}
 
-		if precision > lastPrecision {
-			result = results[i].Value
-		}
+	if interval > 0 && budget > interval {
+		budget = interval
+	}
+
+	return budget
+}
 
-		lastPrecision = precision
+// enqueue pushes `req` to the requests queue and reports whether the receiver should keep sending requests.
+func (r *SensorsReader) enqueue(req request) bool {
+	switch err := r.queue.push(req); err {
+	case nil:
+	case ErrQueueClosed:
+		return false
+	default:
+		shared.Logger.Warning(errors.Wrapf(err, "request #%d for %v is discarded", req.ID, req.Metrics))
 	}
 
-	return
+	return true
+}
+
+func maxConcurrentHarvests() int {
+	if n := viper.GetInt("engine.queue.max_concurrent_harvests"); n > 0 {
+		return n
+	}
+
+	return 1
+}
+
+func (r *SensorsReader) nextRequestID() uint64 {
+	return atomic.AddUint64(&r.lastRequestID, 1)
+}
+
+func (r *SensorsReader) nextReceiverID() uint64 {
+	return atomic.AddUint64(&r.lastReceiverID, 1)
 }
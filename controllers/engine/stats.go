@@ -0,0 +1,277 @@
+package engine
+
+import (
+	"sync"
+	"time"
+)
+
+// latencyBuckets defines upper bounds of the latency Histogram buckets.
+var latencyBuckets = []time.Duration{
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+type (
+	// EngineStats defines snapshot of the SensorsReader performance statistics.
+	EngineStats struct {
+		// Since is a time statistics are collected from.
+		Since time.Time
+		// Sensors contains statistics per each sensor, which was harvested at least once.
+		Sensors map[string]SensorStats
+		// Requests contains statistics of the handled requests.
+		Requests RequestStats
+		// Queue contains requests queue metrics.
+		Queue QueueStats
+		// Coalescing contains overlapping requests coalescing counters.
+		Coalescing CoalescingStats
+		// Health contains health state of the sensors, which have failed at least once.
+		Health map[string]SensorHealth
+		// Rejected contains number of implausible readings rejected per sensor.
+		Rejected map[string]uint64
+	}
+
+	// SensorStats defines statistics of the single sensor usage.
+	SensorStats struct {
+		// Harvests is a number of performed harvests.
+		Harvests uint64
+		// Successes is a number of harvests completed without errors.
+		Successes uint64
+		// Timeouts is a number of harvests, which have exceeded reading budget.
+		Timeouts uint64
+		// Errors is a number of harvests failed with errors, including initialization ones.
+		Errors uint64
+		// Inits is a number of performed sensor initializations.
+		Inits uint64
+		// StandbyCloses is a number of times sensor was closed after being idle for standby timeout.
+		StandbyCloses uint64
+		// Latency is a distribution of harvest durations.
+		Latency Histogram
+	}
+
+	// RequestStats defines statistics of the handled requests.
+	RequestStats struct {
+		// Handled is a number of requests results were delivered for.
+		Handled uint64
+		// QueueWait is a distribution of time requests have spent in queue before harvest started.
+		QueueWait Histogram
+		// Latency is a distribution of time from queueing request to delivering its results.
+		Latency Histogram
+	}
+
+	// Histogram defines cumulative distribution of durations over fixed buckets.
+	Histogram struct {
+		// Bounds contains upper bounds of the buckets, the last implicit bucket is unbounded.
+		Bounds []time.Duration
+		// Counts contains number of observations per bucket, having one more item than Bounds.
+		Counts []uint64
+		Count  uint64
+		Sum    time.Duration
+		Max    time.Duration
+	}
+
+	// statsCollector accumulates EngineStats in concurrency safe way.
+	statsCollector struct {
+		mutex    sync.Mutex
+		since    time.Time
+		sensors  map[string]*SensorStats
+		requests RequestStats
+	}
+)
+
+// newHistogram constructs new empty Histogram instance with default latency buckets.
+func newHistogram() Histogram {
+	return Histogram{
+		Bounds: latencyBuckets,
+		Counts: make([]uint64, len(latencyBuckets) + 1),
+	}
+}
+
+// observe adds duration `d` into the Histogram.
+func (h *Histogram) observe(d time.Duration) {
+	var (
+		i = 0
+	)
+
+	for i < len(h.Bounds) && d > h.Bounds[i] {
+		i++
+	}
+
+	h.Counts[i]++
+	h.Count++
+	h.Sum += d
+
+	if d > h.Max {
+		h.Max = d
+	}
+}
+
+// Mean returns average of the observed durations.
+func (h Histogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+
+	return h.Sum / time.Duration(h.Count)
+}
+
+// Quantile returns estimated `q` quantile of the observed durations,
+// which is the upper bound of the bucket it falls into, or Max for the unbounded bucket.
+func (h Histogram) Quantile(q float64) time.Duration {
+	var (
+		rank = uint64(q * float64(h.Count))
+		cumulative uint64
+	)
+
+	if h.Count == 0 {
+		return 0
+	}
+
+	for i, count := range h.Counts {
+		cumulative += count
+
+		if cumulative > rank || cumulative == h.Count {
+			if i < len(h.Bounds) && h.Bounds[i] < h.Max {
+				return h.Bounds[i]
+			}
+
+			return h.Max
+		}
+	}
+
+	return h.Max
+}
+
+func (h Histogram) clone() Histogram {
+	h.Counts = append([]uint64(nil), h.Counts...)
+	return h
+}
+
+// newStatsCollector constructs new statsCollector instance.
+func newStatsCollector() *statsCollector {
+	return &statsCollector{
+		since:   time.Now(),
+		sensors: make(map[string]*SensorStats),
+		requests: RequestStats{
+			QueueWait: newHistogram(),
+			Latency:   newHistogram(),
+		},
+	}
+}
+
+// sensor returns stats of the sensor with given `id`, it must be called with mutex being locked.
+func (c *statsCollector) sensor(id string) *SensorStats {
+	stats, ok := c.sensors[id]
+	if !ok {
+		stats = &SensorStats{
+			Latency: newHistogram(),
+		}
+
+		c.sensors[id] = stats
+	}
+
+	return stats
+}
+
+// harvested registers outcome of the sensor harvest with given `id`, which took `latency`.
+func (c *statsCollector) harvested(id string, latency time.Duration, timedOut, failed bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var (
+		stats = c.sensor(id)
+	)
+
+	stats.Harvests++
+	stats.Latency.observe(latency)
+
+	switch {
+	case timedOut:
+		stats.Timeouts++
+	case failed:
+		stats.Errors++
+	default:
+		stats.Successes++
+	}
+}
+
+// failed registers sensor with given `id` failing before it could be harvested.
+func (c *statsCollector) failed(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.sensor(id).Errors++
+}
+
+// initialized registers initialization of the sensor with given `id`.
+func (c *statsCollector) initialized(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.sensor(id).Inits++
+}
+
+// standbyClosed registers sensor with given `id` being closed due to standby timeout.
+func (c *statsCollector) standbyClosed(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.sensor(id).StandbyCloses++
+}
+
+// forget discards statistics of the sensor with given `id`.
+func (c *statsCollector) forget(id string) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	delete(c.sensors, id)
+}
+
+// queueWaited registers time `req` has spent in queue before harvest started.
+func (c *statsCollector) queueWaited(req request) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.requests.QueueWait.observe(time.Since(req.Queued))
+}
+
+// delivered registers results of the `req` being delivered to its handler.
+func (c *statsCollector) delivered(req request) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.requests.Handled++
+	c.requests.Latency.observe(time.Since(req.Queued))
+}
+
+func (c *statsCollector) snapshot() EngineStats {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	var (
+		stats = EngineStats{
+			Since:   c.since,
+			Sensors: make(map[string]SensorStats, len(c.sensors)),
+			Requests: RequestStats{
+				Handled:   c.requests.Handled,
+				QueueWait: c.requests.QueueWait.clone(),
+				Latency:   c.requests.Latency.clone(),
+			},
+		}
+	)
+
+	for id, s := range c.sensors {
+		ss := *s
+		ss.Latency = s.Latency.clone()
+		stats.Sensors[id] = ss
+	}
+
+	return stats
+}
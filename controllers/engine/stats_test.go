@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestHistogramObserve(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		d      time.Duration
+		bucket int
+	}{
+		{"zero", 0, 0},
+		{"on the first bound", 10 * time.Millisecond, 0},
+		{"above the first bound", 11 * time.Millisecond, 1},
+		{"on the last bound", 10 * time.Second, len(latencyBuckets) - 1},
+		{"unbounded", 11 * time.Second, len(latencyBuckets)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				h = newHistogram()
+				expected = make([]uint64, len(latencyBuckets) + 1)
+			)
+
+			h.observe(tc.d)
+			expected[tc.bucket] = 1
+
+			if fmt.Sprint(h.Counts) != fmt.Sprint(expected) {
+				t.Errorf("expected %v to be observed in bucket %d, got counts %v", tc.d, tc.bucket, h.Counts)
+			}
+
+			if h.Count != 1 || h.Sum != tc.d || h.Max != tc.d {
+				t.Errorf("expected count 1, sum and max %v, got %d, %v, %v", tc.d, h.Count, h.Sum, h.Max)
+			}
+		})
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		observed []time.Duration
+		q        float64
+		expected time.Duration
+	}{
+		{"empty", nil, 0.5, 0},
+		{"bucket bound", []time.Duration{5 * time.Millisecond, 5 * time.Millisecond, 30 * time.Millisecond,
+			20 * time.Second}, 0.25, 10 * time.Millisecond},
+		{"median", []time.Duration{5 * time.Millisecond, 5 * time.Millisecond, 30 * time.Millisecond,
+			20 * time.Second}, 0.5, 50 * time.Millisecond},
+		{"unbounded bucket", []time.Duration{5 * time.Millisecond, 5 * time.Millisecond, 30 * time.Millisecond,
+			20 * time.Second}, 0.75, 20 * time.Second},
+		{"maximum", []time.Duration{5 * time.Millisecond, 5 * time.Millisecond, 30 * time.Millisecond,
+			20 * time.Second}, 1, 20 * time.Second},
+		{"bound clamped to max", []time.Duration{5 * time.Millisecond, 7 * time.Millisecond}, 0.5, 7 * time.Millisecond},
+		{"maximum clamped to max", []time.Duration{5 * time.Millisecond}, 1, 5 * time.Millisecond},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newHistogram()
+
+			for _, d := range tc.observed {
+				h.observe(d)
+			}
+
+			if actual := h.Quantile(tc.q); actual != tc.expected {
+				t.Errorf("expected %v quantile to be %v, got %v", tc.q, tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestStatsSnapshotIsolation(t *testing.T) {
+	var (
+		c = newStatsCollector()
+	)
+
+	c.harvested("HDC1080@1/0x40", 5 * time.Millisecond, false, false)
+	c.delivered(request{Queued: time.Now()})
+
+	snapshot := c.snapshot()
+
+	c.harvested("HDC1080@1/0x40", 5 * time.Millisecond, false, true)
+	c.delivered(request{Queued: time.Now()})
+
+	if s := snapshot.Sensors["HDC1080@1/0x40"]; s.Harvests != 1 || s.Errors != 0 || s.Latency.Counts[0] != 1 {
+		t.Errorf("expected snapshot not to change with further harvests, got %+v", s)
+	}
+
+	if snapshot.Requests.Handled != 1 || snapshot.Requests.Latency.Counts[0] != 1 {
+		t.Errorf("expected snapshot not to change with further deliveries, got %+v", snapshot.Requests)
+	}
+
+	snapshot.Sensors["HDC1080@1/0x40"].Latency.Counts[0] = 100
+	snapshot.Requests.Latency.Counts[0] = 100
+
+	if latest := c.snapshot(); latest.Sensors["HDC1080@1/0x40"].Latency.Counts[0] != 2 ||
+		latest.Requests.Latency.Counts[0] != 2 {
+		t.Error("expected modifications of snapshot not to affect collected statistics")
+	}
+
+	c.forget("HDC1080@1/0x40")
+
+	if _, ok := c.snapshot().Sensors["HDC1080@1/0x40"]; ok {
+		t.Error("expected statistics of removed sensor to be forgotten")
+	}
+}
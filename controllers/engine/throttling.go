@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"sync"
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+)
+
+type (
+	// throttle keeps sensors from being harvested more often than the minimal interval they declare
+	// (see sensor.MinInterval), so that their latest readings are reused instead.
+	throttle struct {
+		mutex  sync.Mutex
+		latest map[string]map[models.Metric]harvested
+	}
+
+	// harvested stores the latest reading of the sensor metric along with the time it was harvested at.
+	harvested struct {
+		at     time.Time
+		result sensor.ReadingResult
+	}
+)
+
+// newThrottle constructs new throttle instance.
+func newThrottle() *throttle {
+	return &throttle{
+		latest: make(map[string]map[models.Metric]harvested),
+	}
+}
+
+// reuse writes the latest readings of the `sn` sensor marked as sensor.Stale into the `pipe`,
+// when all the requested metrics the sensor reads have been harvested within its minimal interval,
+// and reports whether it did so. Otherwise, the sensor must be harvested, and its readings are remembered afterwards.
+func (t *throttle) reuse(sn sensor.Sensor, pipe sensor.ReadingsPipe) bool {
+	var (
+		interval = sensor.MinInterval(sn)
+		reused = make(map[models.Metric]sensor.ReadingResult)
+	)
+
+	if interval <= 0 {
+		return false
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	latest, ok := t.latest[sn.ID()]
+	if !ok {
+		latest = make(map[models.Metric]harvested)
+		t.latest[sn.ID()] = latest
+	}
+
+	// Any requested metric missing from the latest harvest requires the sensor to be read again:
+	for metric := range pipe {
+		if !suitable(sn, metric) {
+			continue
+		}
+
+		h, ok := latest[metric]
+		if !ok || time.Since(h.at) >= interval {
+			return false
+		}
+
+		h.result.Flags |= sensor.Stale
+		reused[metric] = h.result
+	}
+
+	if len(reused) == 0 {
+		return false
+	}
+
+	for metric, result := range reused {
+		select {
+		case pipe[metric] <- result:
+		default:
+		}
+	}
+
+	return true
+}
+
+// remember stores `readings` harvested from the throttled sensors, so that they could be reused by the next requests.
+// Readings are remembered only once they are produced, thus failed harvest won't delay the next attempt.
+func (t *throttle) remember(readings map[models.Metric][]sensor.ReadingResult) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	var (
+		now = time.Now()
+	)
+
+	for metric, results := range readings {
+		for _, result := range results {
+			latest, ok := t.latest[result.Source]
+			if !ok || result.Flags.Has(sensor.Stale) {
+				continue
+			}
+
+			latest[metric] = harvested{
+				at:     now,
+				result: result,
+			}
+		}
+	}
+}
+
+// forget discards the latest harvest of the sensor with given `id`.
+func (t *throttle) forget(id string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.latest, id)
+}
@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+)
+
+// describedSensor is a sensor.Sensor stub, which declares minimal interval of its readings.
+type describedSensor struct {
+	id       string
+	interval time.Duration
+}
+
+func (s describedSensor) ID() string                { return s.id }
+func (s describedSensor) Init() error               { return nil }
+func (s describedSensor) Harvest(_ *sensor.Context) {}
+func (s describedSensor) Verify() bool              { return true }
+func (s describedSensor) Active() bool              { return true }
+func (s describedSensor) Close() error              { return nil }
+
+func (s describedSensor) Metrics() []models.Metric {
+	return []models.Metric{metrics.Temperature, metrics.Humidity}
+}
+
+func (s describedSensor) Capabilities() sensor.Capabilities {
+	return sensor.Capabilities{
+		metrics.Temperature: {MinInterval: s.interval},
+		metrics.Humidity:    {MinInterval: s.interval},
+	}
+}
+
+func pipeFor(metrics ...models.Metric) sensor.ReadingsPipe {
+	var (
+		pipe = make(sensor.ReadingsPipe)
+	)
+
+	for _, metric := range metrics {
+		pipe[metric] = make(chan sensor.ReadingResult, 1)
+	}
+
+	return pipe
+}
+
+func TestThrottleReusesOnlyHarvestedMetrics(t *testing.T) {
+	var (
+		th = newThrottle()
+		sn = describedSensor{id: "HDC1080@1/0x40", interval: time.Minute}
+	)
+
+	if th.reuse(sn, pipeFor(metrics.Temperature)) {
+		t.Fatal("expected sensor never harvested before to be read")
+	}
+
+	th.remember(map[models.Metric][]sensor.ReadingResult{
+		metrics.Temperature: {{Source: sn.ID(), Value: 21}},
+	})
+
+	pipe := pipeFor(metrics.Temperature)
+	if !th.reuse(sn, pipe) {
+		t.Fatal("expected temperature harvested within interval to be reused")
+	}
+
+	if result := <-pipe[metrics.Temperature]; result.Value != 21 || !result.Flags.Has(sensor.Stale) {
+		t.Errorf("expected stale reused temperature, got %+v", result)
+	}
+
+	if th.reuse(sn, pipeFor(metrics.Humidity)) {
+		t.Error("expected sensor to be read for humidity missing from the latest harvest")
+	}
+
+	if th.reuse(sn, pipeFor(metrics.Temperature, metrics.Humidity)) {
+		t.Error("expected sensor to be read when any of the requested metrics is missing from the latest harvest")
+	}
+}
+
+func TestThrottleRetriesFailedHarvest(t *testing.T) {
+	var (
+		th = newThrottle()
+		sn = describedSensor{id: "HDC1080@1/0x40", interval: time.Minute}
+	)
+
+	if th.reuse(sn, pipeFor(metrics.Temperature)) {
+		t.Fatal("expected sensor never harvested before to be read")
+	}
+
+	// Failed harvest produces no readings:
+	th.remember(map[models.Metric][]sensor.ReadingResult{})
+
+	if th.reuse(sn, pipeFor(metrics.Temperature)) {
+		t.Error("expected sensor to be read again after failed harvest")
+	}
+}
+
+func TestThrottleExpiresAfterInterval(t *testing.T) {
+	var (
+		th = newThrottle()
+		sn = describedSensor{id: "HDC1080@1/0x40", interval: 10 * time.Millisecond}
+	)
+
+	th.reuse(sn, pipeFor(metrics.Temperature))
+	th.remember(map[models.Metric][]sensor.ReadingResult{
+		metrics.Temperature: {{Source: sn.ID(), Value: 21}},
+	})
+
+	time.Sleep(2 * sn.interval)
+
+	if th.reuse(sn, pipeFor(metrics.Temperature)) {
+		t.Error("expected sensor to be read once its minimal interval has passed")
+	}
+}
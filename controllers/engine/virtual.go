@@ -0,0 +1,79 @@
+package engine
+
+import (
+	"context"
+
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+)
+
+// virtualsFor returns virtual sensors from `sensors` suitable for reading at least one of the given `metrics`.
+func virtualsFor(sensors sensor.SensorsRegister, metrics []models.Metric) (virtuals []sensor.Virtual) {
+	for _, sn := range sensors {
+		if v, ok := sn.(sensor.Virtual); ok {
+			for _, metric := range metrics {
+				if suitable(sn, metric) {
+					virtuals = append(virtuals, v)
+					break
+				}
+			}
+		}
+	}
+
+	return
+}
+
+// derive harvests `virtuals` sensors providing them with aggregated `results` of the physical sensors as inputs,
+// and then adds derived readings to the `results`.
+func (r *SensorsReader) derive(ctx context.Context, virtuals []sensor.Virtual, results ReadingResults) {
+	var (
+		pipe = make(sensor.ReadingsPipe)
+	)
+
+	for _, v := range virtuals {
+		for _, metric := range v.Metrics() {
+			if _, ok := pipe[metric]; !ok {
+				pipe[metric] = make(chan sensor.ReadingResult, len(virtuals))
+			}
+		}
+	}
+
+	for _, v := range virtuals {
+		readerCtx := sensor.NewReaderContext(ctx, v)
+		readerCtx.Pipe = pipe
+		readerCtx.Inputs = results
+
+		v.Harvest(readerCtx)
+	}
+
+	for metric, result := range r.aggregator.Aggregate(r.plausibility.Filter(drainPipe(pipe))) {
+		// Physically measured value always takes precedence over the derived one:
+		if _, ok := results[metric]; !ok {
+			results[metric] = result
+		}
+	}
+}
+
+// withInputs returns `metrics` extended with input metrics of the `virtuals` sensors.
+func withInputs(metrics []models.Metric, virtuals []sensor.Virtual) []models.Metric {
+	var (
+		extended = append(make([]models.Metric, 0, len(metrics)), metrics...)
+		seen = make(map[models.Metric]bool, len(metrics))
+	)
+
+	for _, metric := range metrics {
+		seen[metric] = true
+	}
+
+	for _, v := range virtuals {
+		for _, metric := range v.Inputs() {
+			if !seen[metric] {
+				seen[metric] = true
+				extended = append(extended, metric)
+			}
+		}
+	}
+
+	return extended
+}
@@ -0,0 +1,31 @@
+package engine
+
+import (
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// Available policies for readings of the sensors, which are still warming up (see sensor.WarmingUp).
+const (
+	// ProvisionalWarmUpPolicy passes readings through, marking them with sensor.Provisional quality flag.
+	ProvisionalWarmUpPolicy = "provisional"
+	// HoldWarmUpPolicy holds readings back until sensor is warmed up.
+	HoldWarmUpPolicy = "hold"
+)
+
+// holdWarmingUp applies warm-up policy to the acquired `sn` sensor, which readings are collected within `ctx`,
+// and reports whether reading of the sensor must be skipped since it isn't warmed up yet.
+func (r *SensorsReader) holdWarmingUp(sn sensor.Sensor, ctx *sensor.Context) bool {
+	if r.sensors.Ready(sn) {
+		return false
+	}
+
+	if r.warmUpPolicy == HoldWarmUpPolicy {
+		shared.Logger.Debugf("Sensor %s is warming up, its readings are held back", sensor.DisplayName(sn.ID()))
+		return true
+	}
+
+	ctx.Flags |= sensor.Provisional
+
+	return false
+}
@@ -0,0 +1,222 @@
+package engine
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/controllers/storage"
+	"github.com/timoth-y/chainmetric-iot/model"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+type (
+	// Window accumulates statistics of the readings sampled over the aggregation period,
+	// persisting its state on each sample, so that it survives device restarts.
+	Window struct {
+		mutex  sync.Mutex
+		id     string
+		limits models.RequirementsMap
+		state  windowState
+		// discarded is set once the Window state is removed, so that late samples won't persist it again.
+		discarded bool
+	}
+
+	// WindowReceiverFunc defines handler of the readings aggregated over the Window period,
+	// which receives the latest `readings` along with `summary` statistics of the whole period.
+	WindowReceiverFunc func(readings ReadingResults, summary map[models.Metric]model.WindowSummary)
+
+	windowState struct {
+		Start time.Time                      `json:"start"`
+		Stats map[models.Metric]*windowStats `json:"stats"`
+	}
+
+	// windowStats accumulates mean and variance with Welford's online algorithm.
+	windowStats struct {
+		Min    float64       `json:"min"`
+		Max    float64       `json:"max"`
+		Mean   float64       `json:"mean"`
+		M2     float64       `json:"m2"`
+		Count  int           `json:"count"`
+		Above  time.Duration `json:"above"`
+		Below  time.Duration `json:"below"`
+		Last   float64       `json:"last"`
+		LastAt time.Time     `json:"last_at"`
+	}
+)
+
+// NewWindow constructs new Window instance with given `id`, restoring its persisted state if such exists.
+// Time readings stay beyond the requirements `limits` is accounted in the window summary.
+func NewWindow(id string, limits models.RequirementsMap) *Window {
+	var (
+		w = &Window{
+			id:     id,
+			limits: limits,
+		}
+	)
+
+	if restored, err := storage.LoadWindowState(id, &w.state); err != nil {
+		shared.Logger.Error(errors.Wrapf(err, "failed to restore state of window '%s'", id))
+	} else if restored {
+		shared.Logger.Debugf("Window '%s' is restored with readings sampled since %v", id, w.state.Start)
+	}
+
+	if w.state.Stats == nil {
+		w.state = newWindowState(time.Now())
+	}
+
+	return w
+}
+
+// Elapsed returns time passed since the Window has started.
+func (w *Window) Elapsed() time.Duration {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return time.Since(w.state.Start)
+}
+
+// Add accumulates `readings` into the Window statistics.
+func (w *Window) Add(readings ReadingResults) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	for metric, result := range readings {
+		var (
+			stats, ok = w.state.Stats[metric]
+			at = result.Timestamp
+		)
+
+		if math.IsNaN(result.Value) || math.IsInf(result.Value, 0) {
+			continue
+		}
+
+		if at.IsZero() {
+			at = time.Now()
+		}
+
+		if !ok {
+			stats = &windowStats{
+				Min: result.Value,
+				Max: result.Value,
+			}
+
+			w.state.Stats[metric] = stats
+		} else {
+			w.accountLimits(metric, stats, at)
+		}
+
+		stats.Count++
+		stats.Min = math.Min(stats.Min, result.Value)
+		stats.Max = math.Max(stats.Max, result.Value)
+
+		delta := result.Value - stats.Mean
+		stats.Mean += delta / float64(stats.Count)
+		stats.M2 += delta * (result.Value - stats.Mean)
+
+		stats.Last = result.Value
+		stats.LastAt = at
+	}
+
+	w.persist()
+}
+
+// Flush returns summary of the readings accumulated up to the given `end` time and starts the new Window period.
+func (w *Window) Flush(end time.Time) map[models.Metric]model.WindowSummary {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	var (
+		summary = make(map[models.Metric]model.WindowSummary, len(w.state.Stats))
+	)
+
+	for metric, stats := range w.state.Stats {
+		w.accountLimits(metric, stats, end)
+
+		summary[metric] = model.WindowSummary{
+			Min:            stats.Min,
+			Max:            stats.Max,
+			Mean:           stats.Mean,
+			StdDev:         math.Sqrt(stats.M2 / float64(stats.Count)),
+			Count:          stats.Count,
+			TimeAboveLimit: stats.Above.Seconds(),
+			TimeBelowLimit: stats.Below.Seconds(),
+		}
+	}
+
+	w.state = newWindowState(end)
+	w.persist()
+
+	return summary
+}
+
+// accountLimits adds time passed since the last sample up to `at` to the time beyond limits,
+// assuming the last sampled value held for the whole interval.
+func (w *Window) accountLimits(metric models.Metric, stats *windowStats, at time.Time) {
+	var (
+		limit, ok = w.limits[metric]
+		held = at.Sub(stats.LastAt)
+	)
+
+	if !ok || !Limited(limit) || held <= 0 {
+		return
+	}
+
+	switch {
+	case AboveLimit(limit, stats.Last):
+		stats.Above += held
+	case BelowLimit(limit, stats.Last):
+		stats.Below += held
+	}
+}
+
+// Discard removes persisted state of the Window, which is no longer needed once its request is canceled.
+func (w *Window) Discard() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.discarded = true
+
+	if err := storage.DeleteWindowState(w.id); err != nil {
+		shared.Logger.Warning(errors.Wrapf(err, "failed to remove state of window '%s'", w.id))
+	}
+}
+
+func (w *Window) persist() {
+	if w.discarded {
+		return
+	}
+
+	if err := storage.SaveWindowState(w.id, w.state); err != nil {
+		shared.Logger.Warning(errors.Wrapf(err, "failed to persist state of window '%s'", w.id))
+	}
+}
+
+func newWindowState(start time.Time) windowState {
+	return windowState{
+		Start: start,
+		Stats: make(map[models.Metric]*windowStats),
+	}
+}
+
+// SubscribeWindowedReceiver subscribes `handler` to receive readings summary accumulated by the `window`
+// over each `period`, while metrics are sampled with higher internal `sampling` rate.
+func (r *SensorsReader) SubscribeWindowedReceiver(
+	ctx context.Context,
+	window *Window,
+	handler WindowReceiverFunc,
+	period, sampling time.Duration,
+	metrics ...models.Metric,
+) context.CancelFunc {
+	return r.SubscribeReceiver(ctx, func(readings ReadingResults) {
+		window.Add(readings)
+
+		if window.Elapsed() >= period {
+			handler(readings, window.Flush(time.Now()))
+		}
+	}, sampling, metrics...)
+}
@@ -0,0 +1,191 @@
+package engine
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/controllers/storage"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+func TestWindowRestoresAfterRestart(t *testing.T) {
+	openWindowsDB(t)
+
+	var (
+		start = time.Now().Add(-time.Minute)
+		window = NewWindow("request-1", nil)
+	)
+
+	window.Add(ReadingResults{metrics.Temperature: {Value: 4, Timestamp: start}})
+	window.Add(ReadingResults{metrics.Temperature: {Value: 6, Timestamp: start.Add(10 * time.Second)}})
+
+	// Device is restarted, so the window is constructed once again for the same request:
+	var (
+		restored = NewWindow("request-1", nil)
+	)
+
+	restored.Add(ReadingResults{metrics.Temperature: {Value: 8, Timestamp: start.Add(20 * time.Second)}})
+
+	summary := restored.Flush(start.Add(30 * time.Second))[metrics.Temperature]
+
+	if summary.Count != 3 || summary.Min != 4 || summary.Max != 8 || summary.Mean != 6 {
+		t.Errorf("expected readings sampled before restart to be restored, got %+v", summary)
+	}
+
+	if elapsed := restored.Elapsed(); elapsed > time.Minute {
+		t.Errorf("expected new period to start on flush, got %v elapsed", elapsed)
+	}
+
+	restored.Discard()
+
+	if ok, err := storage.LoadWindowState("request-1", &windowState{}); ok || err != nil {
+		t.Errorf("expected window state to be removed once discarded, got %v, %v", ok, err)
+	}
+
+	// Samples arriving after the request is canceled must not bring the state back:
+	restored.Add(ReadingResults{metrics.Temperature: {Value: 8, Timestamp: start.Add(40 * time.Second)}})
+
+	if ok, _ := storage.LoadWindowState("request-1", &windowState{}); ok {
+		t.Error("expected discarded window state not to be persisted again")
+	}
+}
+
+func TestWindowLimitsAccounting(t *testing.T) {
+	openWindowsDB(t)
+
+	var (
+		start = time.Now().Add(-time.Hour)
+	)
+
+	for _, tc := range []struct {
+		name   string
+		limit  models.Requirement
+		values []float64
+		above  float64
+		below  float64
+	}{
+		{"within limits", models.Requirement{MinLimit: 2, MaxLimit: 8}, []float64{4, 5, 6}, 0, 0},
+		{"above max", models.Requirement{MinLimit: 2, MaxLimit: 8}, []float64{4, 9, 10, 5}, 20, 0},
+		{"below min", models.Requirement{MinLimit: 2, MaxLimit: 8}, []float64{1, 5, 6, 7}, 0, 10},
+		{"below zero min", models.Requirement{MinLimit: 0, MaxLimit: 8}, []float64{-2, 4, 9, 4}, 10, 10},
+		{"above zero max", models.Requirement{MinLimit: -25, MaxLimit: 0}, []float64{-18, 1, -18, -30}, 10, 10},
+		{"min only", models.Requirement{MinLimit: 2, MaxLimit: 0}, []float64{4, 1, 5, 6}, 0, 10},
+		{"max only", models.Requirement{MinLimit: 0, MaxLimit: -18}, []float64{-20, -10, -20, -20}, 10, 0},
+		{"last value held until flush", models.Requirement{MinLimit: 2, MaxLimit: 8}, []float64{4, 5, 6, 12}, 10, 0},
+		{"not limited", models.Requirement{}, []float64{-5, 100, 5}, 0, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				window = NewWindow(t.Name(), models.RequirementsMap{metrics.Temperature: tc.limit})
+				end time.Time
+			)
+
+			// Readings are sampled each 10 seconds and the window is flushed 10 seconds after the last one:
+			for i, value := range tc.values {
+				window.Add(ReadingResults{metrics.Temperature: {
+					Value:     value,
+					Timestamp: start.Add(time.Duration(i) * 10 * time.Second),
+				}})
+				end = start.Add(time.Duration(i + 1) * 10 * time.Second)
+			}
+
+			summary := window.Flush(end)[metrics.Temperature]
+
+			if summary.TimeAboveLimit != tc.above {
+				t.Errorf("expected %vs above limit, got %vs", tc.above, summary.TimeAboveLimit)
+			}
+
+			if summary.TimeBelowLimit != tc.below {
+				t.Errorf("expected %vs below limit, got %vs", tc.below, summary.TimeBelowLimit)
+			}
+		})
+	}
+}
+
+func TestWindowFlushAtPeriodBoundaries(t *testing.T) {
+	openWindowsDB(t)
+
+	var (
+		start = time.Now().Add(-time.Hour)
+		window = NewWindow("request-1", models.RequirementsMap{metrics.Humidity: {MinLimit: 30, MaxLimit: 60}})
+	)
+
+	for _, tc := range []struct {
+		name     string
+		readings []ReadingResults
+		end      time.Duration
+		summary  map[models.Metric][4]float64 // count, min, max, mean
+		stdDev   float64
+		above    float64
+	}{
+		{"first period", []ReadingResults{
+			{metrics.Temperature: {Value: 2, Timestamp: start}, metrics.Humidity: {Value: 50, Timestamp: start}},
+			{metrics.Temperature: {Value: 4, Timestamp: start.Add(10 * time.Second)},
+				metrics.Humidity: {Value: 70, Timestamp: start.Add(10 * time.Second)}},
+			{metrics.Temperature: {Value: 6, Timestamp: start.Add(20 * time.Second)},
+				metrics.Humidity: {Value: 50, Timestamp: start.Add(20 * time.Second)}},
+		}, 30 * time.Second, map[models.Metric][4]float64{
+			metrics.Temperature: {3, 2, 6, 4},
+			metrics.Humidity:    {3, 50, 70, 170.0 / 3},
+		}, math.Sqrt(8.0 / 3), 10},
+		{"second period starts over", []ReadingResults{
+			{metrics.Temperature: {Value: 10, Timestamp: start.Add(40 * time.Second)}},
+			{metrics.Temperature: {Value: math.NaN(), Timestamp: start.Add(50 * time.Second)}},
+		}, time.Minute, map[models.Metric][4]float64{
+			metrics.Temperature: {1, 10, 10, 10},
+		}, 0, 0},
+		{"empty period", nil, 90 * time.Second, map[models.Metric][4]float64{}, 0, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, readings := range tc.readings {
+				window.Add(readings)
+			}
+
+			summary := window.Flush(start.Add(tc.end))
+
+			if len(summary) != len(tc.summary) {
+				t.Fatalf("expected summary of %d metrics, got %v", len(tc.summary), summary)
+			}
+
+			for metric, expected := range tc.summary {
+				actual := summary[metric]
+
+				if float64(actual.Count) != expected[0] || actual.Min != expected[1] || actual.Max != expected[2] ||
+					math.Abs(actual.Mean - expected[3]) > 1e-9 {
+					t.Errorf("expected '%s' summary %v, got %+v", metric, expected, actual)
+				}
+			}
+
+			if stdDev := summary[metrics.Temperature].StdDev; math.Abs(stdDev - tc.stdDev) > 1e-9 {
+				t.Errorf("expected temperature standard deviation %v, got %v", tc.stdDev, stdDev)
+			}
+
+			if above := summary[metrics.Humidity].TimeAboveLimit; above != tc.above {
+				t.Errorf("expected %vs of humidity above limit, got %vs", tc.above, above)
+			}
+		})
+	}
+}
+
+// openWindowsDB opens temporary LevelDB for persisting windows state for the duration of the test.
+func openWindowsDB(t *testing.T) {
+	shared.Logger = logging.MustGetLogger("test")
+
+	db, err := leveldb.OpenFile(t.TempDir(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	shared.LevelDB = db
+
+	t.Cleanup(func() {
+		shared.LevelDB = nil
+		_ = db.Close()
+	})
+}
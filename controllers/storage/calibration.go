@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/utils"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// CalibrationStore provides access to per-sensor sensor.Calibration records persisted in local cache DB.
+// It implements sensor.CalibrationProvider, serving records from memory to keep readings writes cheap.
+type CalibrationStore struct {
+	mutex sync.RWMutex
+	cache map[string]sensor.Calibration
+}
+
+// NewCalibrationStore constructs new CalibrationStore instance and loads all stored calibration records.
+func NewCalibrationStore() (*CalibrationStore, error) {
+	if shared.LevelDB == nil {
+		return nil, errors.New("calibration store won't work without LevelDB available")
+	}
+
+	var (
+		store = &CalibrationStore{
+			cache: make(map[string]sensor.Calibration),
+		}
+		prefix = []byte(utils.FormCompositeKey("calibration"))
+		iter = shared.LevelDB.NewIterator(util.BytesPrefix(prefix), nil)
+	)
+
+	defer iter.Release()
+
+	for iter.Next() {
+		var (
+			key = string(iter.Key())
+			calibration sensor.Calibration
+		)
+
+		if err := json.Unmarshal(iter.Value(), &calibration); err != nil {
+			shared.Logger.Error(errors.Wrapf(err, "failed to unmarshal calibration for key '%s'", key))
+			continue
+		}
+
+		store.cache[key] = calibration
+	}
+
+	return store, errors.Wrap(iter.Error(), "failed to iterate over stored calibrations")
+}
+
+// CalibrationFor returns sensor.Calibration of the `metric` readings from the sensor with given `sensorID`.
+func (s *CalibrationStore) CalibrationFor(sensorID string, metric models.Metric) (sensor.Calibration, bool) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	calibration, ok := s.cache[calibrationKey(sensorID, metric)]
+
+	return calibration, ok
+}
+
+// Put stores given `calibrations`, replacing previous ones for the same sensor and metric.
+// Calibration without explicit version gets the next one after the replaced calibration.
+func (s *CalibrationStore) Put(calibrations ...sensor.Calibration) error {
+	var (
+		batch = new(leveldb.Batch)
+		updated = make(map[string]sensor.Calibration, len(calibrations))
+	)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, calibration := range calibrations {
+		var (
+			key = calibrationKey(calibration.SensorID, calibration.Metric)
+		)
+
+		if len(calibration.SensorID) == 0 || len(calibration.Metric) == 0 {
+			return errors.New("calibration must specify both sensor and metric")
+		}
+
+		if calibration.Version == 0 {
+			if prev, ok := updated[key]; ok {
+				calibration.Version = prev.Version + 1
+			} else {
+				calibration.Version = s.cache[key].Version + 1
+			}
+		}
+
+		value, err := json.Marshal(calibration)
+		if err != nil {
+			return errors.Wrapf(err, "failed to marshal calibration for key '%s'", key)
+		}
+
+		batch.Put([]byte(key), value)
+		updated[key] = calibration
+	}
+
+	if err := shared.LevelDB.Write(batch, nil); err != nil {
+		return err
+	}
+
+	for key, calibration := range updated {
+		s.cache[key] = calibration
+	}
+
+	return nil
+}
+
+// Import stores given `calibrations` loaded from the calibration table (see LoadCalibrationsFile),
+// skipping ones which are identical to already stored, so that re-importing the same table won't bump versions.
+func (s *CalibrationStore) Import(calibrations ...sensor.Calibration) (imported int, err error) {
+	var (
+		changed []sensor.Calibration
+	)
+
+	for _, calibration := range calibrations {
+		stored, ok := s.CalibrationFor(calibration.SensorID, calibration.Metric)
+		if ok && sameCalibration(stored, calibration) {
+			continue
+		}
+
+		changed = append(changed, calibration)
+	}
+
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	return len(changed), s.Put(changed...)
+}
+
+// Delete removes calibration of the `metric` readings from the sensor with given `sensorID`.
+func (s *CalibrationStore) Delete(sensorID string, metric models.Metric) error {
+	var (
+		key = calibrationKey(sensorID, metric)
+	)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := shared.LevelDB.Delete([]byte(key), nil); err != nil {
+		return err
+	}
+
+	delete(s.cache, key)
+
+	return nil
+}
+
+// List returns all stored calibration records.
+func (s *CalibrationStore) List() []sensor.Calibration {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	var (
+		calibrations = make([]sensor.Calibration, 0, len(s.cache))
+	)
+
+	for _, calibration := range s.cache {
+		calibrations = append(calibrations, calibration)
+	}
+
+	return calibrations
+}
+
+func calibrationKey(sensorID string, metric models.Metric) string {
+	return utils.FormCompositeKey("calibration", sensorID, string(metric))
+}
+
+func sameCalibration(stored, loaded sensor.Calibration) bool {
+	if stored.Offset != loaded.Offset ||
+		!stored.Date.Equal(loaded.Date) ||
+		stored.CertificateID != loaded.CertificateID ||
+		len(stored.Coefficients) != len(loaded.Coefficients) ||
+		loaded.Version != 0 && loaded.Version != stored.Version {
+		return false
+	}
+
+	for i := range stored.Coefficients {
+		if stored.Coefficients[i] != loaded.Coefficients[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"encoding/csv"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/timoth-y/chainmetric-core/models"
+	"gopkg.in/yaml.v2"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+)
+
+// calibrationCSVColumns defines expected header of the calibration table in CSV format.
+var calibrationCSVColumns = []string{"sensor", "metric", "offset", "coefficients", "date", "certificate", "version"}
+
+// LoadCalibrationsFile reads calibration table from file by given `path`.
+// Depending on file extension it is parsed either as YAML document with `calibrations` list,
+// or as CSV table with header containing `sensor`, `metric`, `offset`, `coefficients`, `date`, `certificate`,
+// and `version` columns, where polynomial coefficients are separated by semicolon.
+func LoadCalibrationsFile(path string) ([]sensor.Calibration, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return loadCalibrationsYAML(path)
+	case ".csv":
+		return loadCalibrationsCSV(path)
+	default:
+		return nil, errors.Errorf("unsupported calibration file format '%s'", filepath.Ext(path))
+	}
+}
+
+func loadCalibrationsYAML(path string) ([]sensor.Calibration, error) {
+	var (
+		table struct {
+			Calibrations []sensor.Calibration `yaml:"calibrations"`
+		}
+	)
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = yaml.Unmarshal(data, &table); err != nil {
+		return nil, errors.Wrap(err, "failed to parse calibration table")
+	}
+
+	return table.Calibrations, nil
+}
+
+func loadCalibrationsCSV(path string) ([]sensor.Calibration, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	var (
+		reader = csv.NewReader(f)
+		columns = make(map[string]int)
+		calibrations []sensor.Calibration
+	)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read calibration table header")
+	}
+
+	for i := range header {
+		columns[strings.ToLower(strings.TrimSpace(header[i]))] = i
+	}
+
+	for _, column := range calibrationCSVColumns[:2] {
+		if _, ok := columns[column]; !ok {
+			return nil, errors.Errorf("calibration table is missing required '%s' column", column)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "failed to read calibration table on line %d", line)
+		}
+
+		calibration, err := parseCalibrationRecord(record, columns)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid calibration on line %d", line)
+		}
+
+		calibrations = append(calibrations, calibration)
+	}
+
+	return calibrations, nil
+}
+
+func parseCalibrationRecord(record []string, columns map[string]int) (c sensor.Calibration, err error) {
+	var (
+		field = func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+
+			return ""
+		}
+	)
+
+	c.SensorID = field("sensor")
+	c.Metric = models.Metric(field("metric"))
+	c.CertificateID = field("certificate")
+
+	if v := field("offset"); len(v) != 0 {
+		if c.Offset, err = strconv.ParseFloat(v, 64); err != nil {
+			return c, errors.Wrap(err, "failed to parse offset")
+		}
+	}
+
+	if v := field("coefficients"); len(v) != 0 {
+		for _, coefficient := range strings.Split(v, ";") {
+			value, err := strconv.ParseFloat(strings.TrimSpace(coefficient), 64)
+			if err != nil {
+				return c, errors.Wrap(err, "failed to parse coefficients")
+			}
+
+			c.Coefficients = append(c.Coefficients, value)
+		}
+	}
+
+	if v := field("date"); len(v) != 0 {
+		if c.Date, err = parseCalibrationDate(v); err != nil {
+			return c, errors.Wrap(err, "failed to parse date")
+		}
+	}
+
+	if v := field("version"); len(v) != 0 {
+		if c.Version, err = strconv.Atoi(v); err != nil {
+			return c, errors.Wrap(err, "failed to parse version")
+		}
+	}
+
+	return c, nil
+}
+
+func parseCalibrationDate(v string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", v); err == nil {
+		return date, nil
+	}
+
+	return time.Parse(time.RFC3339, v)
+}
@@ -12,14 +12,28 @@ import (
 	"github.com/timoth-y/chainmetric-core/models"
 	"github.com/timoth-y/chainmetric-core/utils"
 
+	"github.com/timoth-y/chainmetric-iot/model"
 	"github.com/timoth-y/chainmetric-iot/shared"
 )
 
 // ReadingsCacheIteratorFunc defines function called by sensor readings cache iterator.
-type ReadingsCacheIteratorFunc func(key string, record models.MetricReadings) (toBreak bool, err error)
+type ReadingsCacheIteratorFunc func(key string, record model.MetricReadingsRecord) (toBreak bool, err error)
+
+// cachedReadings defines structure of the cached readings record value.
+//
+// Records cached before readings metadata was introduced contain only values map,
+// thus are decoded with `Values` field being empty.
+type cachedReadings struct {
+	DeviceID   string                                `json:"device_id,omitempty"`
+	Values     map[models.Metric]float64             `json:"values"`
+	Meta       map[models.Metric]model.ReadingMeta   `json:"meta,omitempty"`
+	Excursions []models.Metric                       `json:"excursions,omitempty"`
+	Suppressed int                                   `json:"suppressed,omitempty"`
+	Summary    map[models.Metric]model.WindowSummary `json:"summary,omitempty"`
+}
 
-// CacheReadings stores models.MetricReadings into local cache DB.
-func CacheReadings(readings ...models.MetricReadings) (err error) {
+// CacheReadings stores model.MetricReadingsRecord into local cache DB.
+func CacheReadings(readings ...model.MetricReadingsRecord) (err error) {
 	var (
 		batch = new(leveldb.Batch)
 	)
@@ -33,7 +47,14 @@ func CacheReadings(readings ...models.MetricReadings) (err error) {
 			value []byte
 		)
 
-		if value, err = json.Marshal(reading.Values); err != nil {
+		if value, err = json.Marshal(cachedReadings{
+			DeviceID:   reading.DeviceID,
+			Values:     reading.Values,
+			Meta:       reading.Meta,
+			Excursions: reading.Excursions,
+			Suppressed: reading.Suppressed,
+			Summary:    reading.Summary,
+		}); err != nil {
 			return err
 		}
 
@@ -43,7 +64,7 @@ func CacheReadings(readings ...models.MetricReadings) (err error) {
 	return shared.LevelDB.Write(batch, nil)
 }
 
-// IterateOverCachedReadings performs iteration over all cached models.MetricReadings records.
+// IterateOverCachedReadings performs iteration over all cached model.MetricReadingsRecord records.
 // allowing to `pop` them on fly.
 func IterateOverCachedReadings(ctx context.Context, fn ReadingsCacheIteratorFunc, pop bool) {
 	var (
@@ -61,7 +82,7 @@ func IterateOverCachedReadings(ctx context.Context, fn ReadingsCacheIteratorFunc
 		var (
 			key = string(iter.Key())
 			_, attrs = utils.SplitCompositeKey(key)
-			values map[models.Metric]float64
+			cached cachedReadings
 		)
 
 		if len(attrs) < 2 {
@@ -80,15 +101,22 @@ func IterateOverCachedReadings(ctx context.Context, fn ReadingsCacheIteratorFunc
 			continue
 		}
 
-		if err := json.Unmarshal(iter.Value(), &values); err != nil {
+		if err := decodeCachedReadings(iter.Value(), &cached); err != nil {
 			shared.Logger.Error(errors.Wrapf(err, "failed to unmarshal values for key '%s'", key))
 			continue
 		}
 
-		toBreak, err := fn(key, models.MetricReadings{
-			AssetID: assetID,
-			Timestamp: timestamp,
-			Values: values,
+		toBreak, err := fn(key, model.MetricReadingsRecord{
+			MetricReadings: models.MetricReadings{
+				AssetID: assetID,
+				DeviceID: cached.DeviceID,
+				Timestamp: timestamp,
+				Values: cached.Values,
+			},
+			Meta: cached.Meta,
+			Excursions: cached.Excursions,
+			Suppressed: cached.Suppressed,
+			Summary: cached.Summary,
 		})
 
 		if err != nil {
@@ -109,3 +137,15 @@ func IterateOverCachedReadings(ctx context.Context, fn ReadingsCacheIteratorFunc
 		}
 	}
 }
+
+// decodeCachedReadings decodes cached readings record `value`,
+// falling back to the legacy format containing only values map.
+func decodeCachedReadings(value []byte, cached *cachedReadings) error {
+	if err := json.Unmarshal(value, cached); err == nil && cached.Values != nil {
+		return nil
+	}
+
+	*cached = cachedReadings{}
+
+	return json.Unmarshal(value, &cached.Values)
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/timoth-y/chainmetric-core/utils"
+
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// SavePausedState persists whether the device sensing is `paused` into local cache DB,
+// so that it would stay paused after reboot.
+func SavePausedState(paused bool) error {
+	if shared.LevelDB == nil {
+		return errors.New("paused state won't be persisted without LevelDB available")
+	}
+
+	if !paused {
+		return shared.LevelDB.Delete([]byte(pausedKey()), nil)
+	}
+
+	return shared.LevelDB.Put([]byte(pausedKey()), []byte{1}, nil)
+}
+
+// LoadPausedState determines whether the device sensing was persisted as paused.
+func LoadPausedState() (bool, error) {
+	if shared.LevelDB == nil {
+		return false, nil
+	}
+
+	_, err := shared.LevelDB.Get([]byte(pausedKey()), nil)
+	if err == leveldb.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func pausedKey() string {
+	return utils.FormCompositeKey("device", "paused")
+}
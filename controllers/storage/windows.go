@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/timoth-y/chainmetric-core/utils"
+
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// SaveWindowState persists `state` of the readings aggregation window with given `id` into local cache DB.
+func SaveWindowState(id string, state interface{}) error {
+	if shared.LevelDB == nil {
+		return errors.New("window state won't be persisted without LevelDB available")
+	}
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal state of window '%s'", id)
+	}
+
+	return shared.LevelDB.Put([]byte(windowKey(id)), value, nil)
+}
+
+// LoadWindowState restores persisted state of the readings aggregation window with given `id` into `state`.
+// Returns false if there is no state persisted for such window.
+func LoadWindowState(id string, state interface{}) (bool, error) {
+	if shared.LevelDB == nil {
+		return false, nil
+	}
+
+	value, err := shared.LevelDB.Get([]byte(windowKey(id)), nil)
+	if err == leveldb.ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+
+	if err = json.Unmarshal(value, state); err != nil {
+		return false, errors.Wrapf(err, "failed to unmarshal state of window '%s'", id)
+	}
+
+	return true, nil
+}
+
+// DeleteWindowState removes persisted state of the readings aggregation window with given `id`.
+func DeleteWindowState(id string) error {
+	if shared.LevelDB == nil {
+		return nil
+	}
+
+	return shared.LevelDB.Delete([]byte(windowKey(id)), nil)
+}
+
+func windowKey(id string) string {
+	return utils.FormCompositeKey("window", id)
+}
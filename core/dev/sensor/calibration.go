@@ -0,0 +1,48 @@
+package sensor
+
+import (
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models"
+)
+
+type (
+	// Calibration defines calibration of the specific Sensor instance for a single models.Metric.
+	//
+	// Raw value is first corrected by Offset and then, if Coefficients are specified,
+	// transformed with polynomial c0 + c1*x + c2*x^2 + ... , where ci is an i-th coefficient.
+	Calibration struct {
+		SensorID      string        `yaml:"sensor" json:"sensor"`
+		Metric        models.Metric `yaml:"metric" json:"metric"`
+		Offset        float64       `yaml:"offset" json:"offset"`
+		Coefficients  []float64     `yaml:"coefficients" json:"coefficients,omitempty"`
+		Date          time.Time     `yaml:"date" json:"date"`
+		CertificateID string        `yaml:"certificate" json:"certificate"`
+		Version       int           `yaml:"version" json:"version"`
+	}
+
+	// CalibrationProvider defines source of the Calibration for Sensor readings.
+	CalibrationProvider interface {
+		// CalibrationFor returns Calibration of the `metric` readings from the Sensor with given `sensorID`.
+		CalibrationFor(sensorID string, metric models.Metric) (Calibration, bool)
+	}
+)
+
+// Apply returns calibrated `value`.
+func (c Calibration) Apply(value float64) float64 {
+	var (
+		x = value + c.Offset
+	)
+
+	if len(c.Coefficients) == 0 {
+		return x
+	}
+
+	// Evaluate polynomial with Horner's method:
+	var y float64
+	for i := len(c.Coefficients) - 1; i >= 0; i-- {
+		y = y * x + c.Coefficients[i]
+	}
+
+	return y
+}
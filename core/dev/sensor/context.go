@@ -2,6 +2,7 @@ package sensor
 
 import (
 	"context"
+	"sync"
 
 	"github.com/timoth-y/chainmetric-core/models"
 	"github.com/timoth-y/chainmetric-iot/shared"
@@ -12,6 +13,17 @@ type Context struct {
 	context.Context
 	SensorID string
 	Pipe     ReadingsPipe
+	// Calibrations is an optional source of the Calibration applied to the written readings.
+	Calibrations CalibrationProvider
+	// Inputs contains readings of the other sensors, which are available to Virtual sensors.
+	Inputs map[models.Metric]ReadingResult
+	// Flags are quality flags, which mark all readings written within the Context.
+	Flags QualityFlags
+	// Capabilities are declared by the sensor (see Described) to qualify written readings.
+	Capabilities Capabilities
+
+	mutex     sync.Mutex
+	lastError error
 }
 
 // NewReaderContext constructs new Context instance based on given `parent` context for the given sensor.Sensor.
@@ -20,30 +32,55 @@ func NewReaderContext(parent context.Context, sensor Sensor) *Context {
 		Context: parent,
 		SensorID: sensor.ID(),
 		Pipe: make(ReadingsPipe),
+		Capabilities: CapabilitiesOf(sensor),
 	}
 }
 
 // WriterFor returns MetricWriter for a given models.Metric.
 func (c *Context) WriterFor(metric models.Metric) *MetricWriter {
 	return &MetricWriter{
-		metric,
-		c,
+		metric: metric,
+		ctx:    c,
+		flags:  c.Flags,
 	}
 }
 
+// Input returns reading of the `metric` provided for Virtual sensor.
+func (c *Context) Input(metric models.Metric) (ReadingResult, bool) {
+	reading, ok := c.Inputs[metric]
+	return reading, ok
+}
+
 // Error wraps `err` logging with sensor.Sensor metadata.
 func (c *Context) Error(err error) {
 	if err != nil {
-		shared.Logger.Errorf("%v: %v", c.SensorID, err)
+		shared.Logger.Errorf("%v: %v", DisplayName(c.SensorID), err)
+
+		c.mutex.Lock()
+		c.lastError = err
+		c.mutex.Unlock()
 	}
 }
 
+// Failed determines whether any error has been reported within the reading context.
+func (c *Context) Failed() bool {
+	return c.LastError() != nil
+}
+
+// LastError returns the latest error reported within the reading context.
+func (c *Context) LastError() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.lastError
+}
+
 // Warning wraps `msg` logging with sensor.Sensor metadata.
 func (c *Context) Warning(msg string) {
-	shared.Logger.Errorf("%v: %v", c.SensorID, msg)
+	shared.Logger.Errorf("%v: %v", DisplayName(c.SensorID), msg)
 }
 
 // Info wraps `info` logging with sensor.Sensor metadata.
 func (c *Context) Info(info string) {
-	shared.Logger.Infof("%v: %v", c.SensorID, info)
+	shared.Logger.Infof("%v: %v", DisplayName(c.SensorID), info)
 }
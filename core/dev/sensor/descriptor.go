@@ -0,0 +1,64 @@
+package sensor
+
+import (
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models"
+)
+
+type (
+	// Described defines optional interface for Sensor devices, which declare their measurement capabilities.
+	Described interface {
+		// Capabilities returns measurement Capability of the Sensor device per each models.Metric it reads.
+		Capabilities() Capabilities
+	}
+
+	// Capabilities defines mapping of the models.Metric to its measurement Capability.
+	Capabilities map[models.Metric]Capability
+
+	// Capability describes how the Sensor device measures single models.Metric.
+	Capability struct {
+		// Unit is the unit of measurement of the written values.
+		Unit string `json:"unit,omitempty"`
+		// Min and Max define measurement range of the sensor, which is considered unbounded when both are zero.
+		Min float64 `json:"min,omitempty"`
+		Max float64 `json:"max,omitempty"`
+		// Resolution is the smallest change of value the sensor is able to detect.
+		Resolution float64 `json:"resolution,omitempty"`
+		// Accuracy is the maximum absolute error of the value (±) declared by the sensor manufacturer.
+		Accuracy float64 `json:"accuracy,omitempty"`
+		// MinInterval is the minimal interval between two consecutive readings the sensor is able to provide.
+		MinInterval time.Duration `json:"min_interval,omitempty"`
+	}
+)
+
+// Bounded determines whether the Capability declares measurement range.
+func (c Capability) Bounded() bool {
+	return c.Min != 0 || c.Max != 0
+}
+
+// InRange determines whether the `value` is within measurement range, which is always true for unbounded one.
+func (c Capability) InRange(value float64) bool {
+	return !c.Bounded() || value >= c.Min && value <= c.Max
+}
+
+// CapabilitiesOf returns Capabilities declared by the `sensor`, or nil if it doesn't implement Described interface.
+func CapabilitiesOf(sensor Sensor) Capabilities {
+	if described, ok := sensor.(Described); ok {
+		return described.Capabilities()
+	}
+
+	return nil
+}
+
+// MinInterval returns the minimal interval between two consecutive readings of the `sensor`,
+// which is the longest one among all metrics it reads, or zero if the sensor doesn't declare any.
+func MinInterval(sensor Sensor) (interval time.Duration) {
+	for _, c := range CapabilitiesOf(sensor) {
+		if c.MinInterval > interval {
+			interval = c.MinInterval
+		}
+	}
+
+	return
+}
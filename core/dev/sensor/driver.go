@@ -0,0 +1,238 @@
+package sensor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"periph.io/x/periph/conn/i2c"
+)
+
+type (
+	// Driver defines Sensor device driver, which is registered in drivers registry (see RegisterDriver),
+	// so that the device could be built by driver's name, e.g. from static sensors configuration.
+	Driver struct {
+		// Name is unique name of the driver, by which it is referred.
+		Name string
+		// Addresses are I2C addresses the device can be connected at, with the default one being first.
+		Addresses []uint16
+		// Build constructs Sensor device connected with given `params`.
+		Build func(params DriverParams) (Sensor, error)
+		// NewI2C constructs I2C-based Sensor device connected at `addr` on the `bus`.
+		NewI2C func(addr uint16, bus int) Sensor
+		// Probe identifies whether the device at `addr` on the `bus` is the one supported by the driver,
+		// usually by reading its chip ID. Drivers without probe can't be auto-detected.
+		Probe I2CProbe
+		// Priority defines order in which drivers sharing same address are probed, the higher goes first.
+		Priority int
+	}
+
+	// I2CProbe defines function for identifying I2C device at `addr` on the already opened `bus`.
+	I2CProbe func(bus i2c.Bus, addr uint16) bool
+
+	// DriverParams defines parameters of the Sensor device connection.
+	DriverParams struct {
+		// Bus is a number of the peripheral bus the device is connected to.
+		Bus int
+		// Address is an address of the device on the bus.
+		Address uint16
+		// Pin is a name of the GPIO pin the device is connected to.
+		Pin string
+		// Options are driver specific settings.
+		Options map[string]interface{}
+	}
+)
+
+var (
+	driversMutex = sync.RWMutex{}
+	drivers      = make(map[string]Driver)
+)
+
+// RegisterDriver adds `driver` to the drivers registry.
+// It is meant to be called from init function of the drivers package and panics if driver name is already taken.
+// Thus, drivers maintained outside of this repository can be added by importing their package for side effects.
+func RegisterDriver(driver Driver) {
+	driversMutex.Lock()
+	defer driversMutex.Unlock()
+
+	if driver.Build == nil {
+		panic("sensor: driver '" + driver.Name + "' has no build function")
+	}
+
+	if _, ok := drivers[driverKey(driver.Name)]; ok {
+		panic("sensor: driver '" + driver.Name + "' is already registered")
+	}
+
+	drivers[driverKey(driver.Name)] = driver
+}
+
+// LookupDriver returns Driver registered with given `name`, which is matched case insensitively.
+func LookupDriver(name string) (Driver, bool) {
+	driversMutex.RLock()
+	defer driversMutex.RUnlock()
+
+	driver, ok := drivers[driverKey(name)]
+	return driver, ok
+}
+
+// Drivers returns all registered drivers sorted by their names.
+func Drivers() []Driver {
+	driversMutex.RLock()
+	defer driversMutex.RUnlock()
+
+	var (
+		list = make([]Driver, 0, len(drivers))
+	)
+
+	for _, driver := range drivers {
+		list = append(list, driver)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// DriversAt returns auto-detectable drivers of the devices which can be connected at I2C `addr`,
+// in order they should be probed.
+func DriversAt(addr uint16) []Driver {
+	driversMutex.RLock()
+	defer driversMutex.RUnlock()
+
+	var (
+		list []Driver
+	)
+
+	for _, driver := range drivers {
+		if driver.Probe != nil && driver.NewI2C != nil && containsAddress(driver.Addresses, addr) {
+			list = append(list, driver)
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Priority != list[j].Priority {
+			return list[i].Priority > list[j].Priority
+		}
+
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// I2CAddresses returns sorted I2C addresses, at which auto-detectable devices can be connected.
+func I2CAddresses() []uint16 {
+	driversMutex.RLock()
+	defer driversMutex.RUnlock()
+
+	var (
+		seen = make(map[uint16]bool)
+		addresses []uint16
+	)
+
+	for _, driver := range drivers {
+		if driver.Probe == nil || driver.NewI2C == nil {
+			continue
+		}
+
+		for _, addr := range driver.Addresses {
+			if !seen[addr] {
+				seen[addr] = true
+				addresses = append(addresses, addr)
+			}
+		}
+	}
+
+	sort.Slice(addresses, func(i, j int) bool {
+		return addresses[i] < addresses[j]
+	})
+
+	return addresses
+}
+
+// WithProbe returns copy of the Driver, which can be auto-detected with given `probe`.
+func (d Driver) WithProbe(probe I2CProbe) Driver {
+	d.Probe = probe
+	return d
+}
+
+// WithPriority returns copy of the Driver with given probing `priority`.
+func (d Driver) WithPriority(priority int) Driver {
+	d.Priority = priority
+	return d
+}
+
+// ChipIDProbe provides I2CProbe, which identifies device by `id` value stored in its `reg` register.
+func ChipIDProbe(reg, id byte) I2CProbe {
+	return func(bus i2c.Bus, addr uint16) bool {
+		var (
+			b = make([]byte, 1)
+		)
+
+		if err := bus.Tx(addr, []byte{reg}, b); err != nil {
+			return false
+		}
+
+		return b[0] == id
+	}
+}
+
+// I2CDriver constructs Driver for I2C-based Sensor device built by `factory`,
+// which can be connected at given `addresses`, with the first one being used when address isn't specified.
+func I2CDriver(name string, factory func(addr uint16, bus int) Sensor, addresses ...uint16) Driver {
+	return Driver{
+		Name:      name,
+		Addresses: addresses,
+		NewI2C:    factory,
+		Build: func(params DriverParams) (Sensor, error) {
+			var (
+				addr = params.Address
+			)
+
+			if addr == 0 {
+				if len(addresses) == 0 {
+					return nil, errors.Errorf("I2C address must be specified for '%s' driver", name)
+				}
+
+				addr = addresses[0]
+			}
+
+			if len(addresses) != 0 && !containsAddress(addresses, addr) {
+				return nil, errors.Errorf("'%s' can't be connected at 0x%02X address, supported ones are %s",
+					name, addr, formatAddresses(addresses))
+			}
+
+			return factory(addr, params.Bus), nil
+		},
+	}
+}
+
+func driverKey(name string) string {
+	return strings.ToUpper(name)
+}
+
+func containsAddress(addresses []uint16, addr uint16) bool {
+	for i := range addresses {
+		if addresses[i] == addr {
+			return true
+		}
+	}
+
+	return false
+}
+
+func formatAddresses(addresses []uint16) string {
+	var (
+		formatted = make([]string, len(addresses))
+	)
+
+	for i := range addresses {
+		formatted[i] = fmt.Sprintf("0x%02X", addresses[i])
+	}
+
+	return strings.Join(formatted, ", ")
+}
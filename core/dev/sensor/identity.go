@@ -0,0 +1,193 @@
+package sensor
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// instanceIDSeparator separates driver ID and location in the Sensor instance ID.
+const instanceIDSeparator = "@"
+
+var (
+	aliasesMutex = sync.RWMutex{}
+	// aliases maps lower-cased instance IDs to human readable aliases assigned to them.
+	aliases = make(map[string]string)
+	// instances holds lower-cased IDs of the Sensor instances identified with WithID, which aliases can't collide with.
+	instances = make(map[string]bool)
+)
+
+// identified wraps Sensor to override its ID, while keeping optional interfaces it implements available.
+type identified struct {
+	Sensor
+	id string
+}
+
+// WithID returns `sensor` identified by given `id` instead of its own ID.
+// Virtual sensors are identified by their own configuration, thus aren't meant to be wrapped.
+func WithID(sensor Sensor, id string) Sensor {
+	if len(id) == 0 || id == sensor.ID() {
+		return sensor
+	}
+
+	registerInstance(id)
+
+	return &identified{
+		Sensor: Unwrap(sensor),
+		id:     id,
+	}
+}
+
+// Unwrap returns original Sensor, which ID was overridden with WithID, or the `sensor` itself.
+func Unwrap(sensor Sensor) Sensor {
+	if s, ok := sensor.(*identified); ok {
+		return s.Sensor
+	}
+
+	return sensor
+}
+
+// InstanceID forms ID of the Sensor instance from the ID its driver declares and the `location` it is connected at,
+// e.g. "MAX44009@1/0x4A", so that devices sharing same driver could be told apart.
+func InstanceID(driverID, location string) string {
+	if len(location) == 0 {
+		return driverID
+	}
+
+	return driverID + instanceIDSeparator + location
+}
+
+// WithLocation returns `sensor` identified by the instance ID formed from its own ID and the `location`.
+func WithLocation(sensor Sensor, location string) Sensor {
+	return WithID(sensor, InstanceID(Unwrap(sensor).ID(), location))
+}
+
+// DriverID returns ID declared by the driver, from which Sensor instance `id` is formed (see InstanceID).
+func DriverID(id string) string {
+	if i := strings.Index(id, instanceIDSeparator); i > 0 {
+		return id[:i]
+	}
+
+	return id
+}
+
+// RegisterAlias assigns human readable `alias` to the Sensor instance `id`.
+// Alias must be unique across the instances, and case insensitively distinct from the instance IDs.
+func RegisterAlias(id, alias string) error {
+	aliasesMutex.Lock()
+	defer aliasesMutex.Unlock()
+
+	if len(alias) == 0 {
+		return nil
+	}
+
+	if _, ok := aliases[strings.ToLower(alias)]; ok || instances[strings.ToLower(alias)] {
+		return errors.Errorf("alias '%s' collides with the sensor instance ID", alias)
+	}
+
+	for other, assigned := range aliases {
+		if strings.EqualFold(assigned, alias) && other != strings.ToLower(id) {
+			return errors.Errorf("alias '%s' is already assigned to '%s' sensor", alias, other)
+		}
+	}
+
+	aliases[strings.ToLower(id)] = alias
+
+	return nil
+}
+
+// registerInstance records Sensor instance `id`, so that it won't be used as an alias.
+// Alias assigned before the instance appeared, which collides with its ID, is revoked.
+func registerInstance(id string) {
+	aliasesMutex.Lock()
+	defer aliasesMutex.Unlock()
+
+	instances[strings.ToLower(id)] = true
+
+	for other, assigned := range aliases {
+		if strings.EqualFold(assigned, id) {
+			shared.Logger.Warningf("Alias '%s' of '%s' sensor collides with the sensor instance ID, thus is revoked",
+				assigned, other)
+			delete(aliases, other)
+		}
+	}
+}
+
+// Alias returns alias assigned to the Sensor instance `id`, or empty string if there is none.
+func Alias(id string) string {
+	aliasesMutex.RLock()
+	defer aliasesMutex.RUnlock()
+
+	return aliases[strings.ToLower(id)]
+}
+
+// DisplayName returns alias assigned to the Sensor instance `id`, or `id` itself if there is none.
+func DisplayName(id string) string {
+	if alias := Alias(id); len(alias) != 0 {
+		return alias
+	}
+
+	return id
+}
+
+// NamesOf returns names by which Sensor instance `id` can be referred, in order of their precedence:
+// instance ID itself, its alias, and ID of its driver, which was used to identify sensors previously.
+func NamesOf(id string) []string {
+	var (
+		names = []string{id}
+	)
+
+	if alias := Alias(id); len(alias) != 0 {
+		names = append(names, alias)
+	}
+
+	if driverID := DriverID(id); driverID != id {
+		names = append(names, driverID)
+	}
+
+	return names
+}
+
+// Refers determines whether `name` refers to the Sensor instance `id` (see NamesOf), case insensitively.
+func Refers(name, id string) bool {
+	for _, n := range NamesOf(id) {
+		if strings.EqualFold(n, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ID returns overridden identifier of the Sensor.
+func (s *identified) ID() string {
+	return s.id
+}
+
+// ReadDuration returns reading duration of the original Sensor, or zero if it doesn't declare one (see Timed).
+func (s *identified) ReadDuration() time.Duration {
+	return ReadDuration(s.Sensor, 0)
+}
+
+// WarmUpDuration returns warm-up duration of the original Sensor, or zero if it doesn't require one (see WarmingUp).
+func (s *identified) WarmUpDuration() time.Duration {
+	return WarmUpDuration(s.Sensor)
+}
+
+// Ready determines whether the original Sensor is ready, which is always true if it doesn't require warm-up.
+func (s *identified) Ready() bool {
+	if w, ok := s.Sensor.(WarmingUp); ok {
+		return w.Ready()
+	}
+
+	return true
+}
+
+// Capabilities returns capabilities declared by the original Sensor (see Described).
+func (s *identified) Capabilities() Capabilities {
+	return CapabilitiesOf(s.Sensor)
+}
@@ -0,0 +1,47 @@
+package sensor
+
+import (
+	"testing"
+)
+
+func TestRegisterAlias(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		id    string
+		alias string
+		valid bool
+	}{
+		{"unique alias", "HDC1080@1/0x40", "freezer-humidity", true},
+		{"same alias reassigned", "HDC1080@1/0x40", "freezer-humidity", true},
+		{"alias of another sensor", "MAX44009@1/0x4A", "Freezer-Humidity", false},
+		{"alias matching instance ID", "MAX44009@1/0x4A", "si1145@1/0x60", false},
+		{"alias matching aliased instance ID", "MAX44009@1/0x4A", "hdc1080@1/0x40", false},
+		{"alias matching configured ID", "MAX44009@1/0x4A", "DOOR-REED", false},
+		{"empty alias", "MAX44009@1/0x4A", "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			WithLocation(&stubSensor{id: "SI1145"}, "1/0x60")
+			WithID(&stubSensor{id: "GPIO-Switch"}, "door-reed")
+
+			if err := RegisterAlias(tc.id, tc.alias); (err == nil) != tc.valid {
+				t.Errorf("expected alias '%s' valid to be %v, got error: %v", tc.alias, tc.valid, err)
+			}
+		})
+	}
+}
+
+func TestInstanceRevokesCollidingAlias(t *testing.T) {
+	if err := RegisterAlias("ADXL345@1/0x53", "LSM303C-A@1/0x1D"); err != nil {
+		t.Fatal(err)
+	}
+
+	sn := WithLocation(&stubSensor{id: "LSM303C-A"}, "1/0x1D")
+
+	if alias := Alias("ADXL345@1/0x53"); len(alias) != 0 {
+		t.Errorf("expected alias colliding with '%s' instance ID to be revoked, got '%s'", sn.ID(), alias)
+	}
+
+	if name := DisplayName(sn.ID()); name != sn.ID() {
+		t.Errorf("expected sensor to be displayed by its instance ID, got '%s'", name)
+	}
+}
@@ -1,14 +1,100 @@
 package sensor
 
 import (
+	"encoding/json"
+	"strings"
+	"time"
+
 	"github.com/timoth-y/chainmetric-core/models"
 )
 
+// Available reading quality flags.
+const (
+	// Estimated flags value, which wasn't measured directly, but derived or approximated.
+	Estimated QualityFlags = 1 << iota
+	// OutOfRange flags value, which exceeds the measurement range of the sensor.
+	OutOfRange
+	// Stale flags value, which wasn't sampled during the current reading, but rather reused.
+	Stale
+	// Provisional flags value, which was read while sensor was still warming up, thus may be inaccurate.
+	Provisional
+)
+
+var (
+	qualityFlagNames = map[QualityFlags]string{
+		Estimated:   "estimated",
+		OutOfRange:  "out_of_range",
+		Stale:       "stale",
+		Provisional: "provisional",
+	}
+)
+
 // ReadingResult defines structure for storing readings result from a single sensor.Sensor device.
 type ReadingResult struct {
-	Source string
-	Value float64
+	Source      string
+	Value       float64
+	Timestamp   time.Time
+	Uncertainty float64
+	Flags       QualityFlags
+	// CalibrationVersion is a version of the Calibration applied to the value, or zero if it is uncalibrated.
+	CalibrationVersion int
 }
 
 // ReadingsPipe maps where to dump sensor.Sensor ReadingResult for concrete models.Metric.
 type ReadingsPipe map[models.Metric] chan ReadingResult
+
+// QualityFlags defines bit set of the reading quality flags.
+type QualityFlags uint8
+
+// Has determines whether all of the given `flags` are set.
+func (f QualityFlags) Has(flags QualityFlags) bool {
+	return f & flags == flags
+}
+
+// Names returns names of the set flags.
+func (f QualityFlags) Names() []string {
+	var (
+		names = make([]string, 0)
+	)
+
+	for flag := Estimated; flag <= Provisional; flag <<= 1 {
+		if f.Has(flag) {
+			names = append(names, qualityFlagNames[flag])
+		}
+	}
+
+	return names
+}
+
+// String returns comma separated names of the set flags.
+func (f QualityFlags) String() string {
+	return strings.Join(f.Names(), ",")
+}
+
+// MarshalJSON encodes flags as list of their names.
+func (f QualityFlags) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.Names())
+}
+
+// UnmarshalJSON decodes flags from list of their names.
+func (f *QualityFlags) UnmarshalJSON(data []byte) error {
+	var (
+		names []string
+	)
+
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+
+	*f = 0
+
+	for flag, name := range qualityFlagNames {
+		for i := range names {
+			if names[i] == name {
+				*f |= flag
+			}
+		}
+	}
+
+	return nil
+}
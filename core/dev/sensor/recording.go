@@ -0,0 +1,245 @@
+package sensor
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/timoth-y/chainmetric-core/models"
+)
+
+type (
+	// RecordedReading defines ReadingResult of the specific models.Metric stored in recording,
+	// where ReadingResult.Source is the ID of the Sensor it was harvested from.
+	RecordedReading struct {
+		Metric models.Metric
+		ReadingResult
+	}
+
+	// Recorder writes harvested readings to the recording file.
+	//
+	// Recording is stored as CSV table with rows of timestamp in unix nanoseconds, sensor ID, metric,
+	// value, uncertainty, quality flags and calibration version, which is gzip compressed when file has `.gz` extension.
+	Recorder struct {
+		mutex  sync.Mutex
+		file   *os.File
+		gzip   *gzip.Writer
+		writer *csv.Writer
+	}
+)
+
+// NewRecorder constructs new Recorder instance, which appends readings to the recording file by given `path`.
+func NewRecorder(path string) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open recording file")
+	}
+
+	var (
+		r = &Recorder{
+			file: file,
+		}
+	)
+
+	// Each recording session is written as separate gzip member, which are read back as a single stream:
+	if compressed(path) {
+		r.gzip = gzip.NewWriter(file)
+		r.writer = csv.NewWriter(r.gzip)
+	} else {
+		r.writer = csv.NewWriter(file)
+	}
+
+	return r, nil
+}
+
+// Record writes `readings` harvested for each metric and flushes them to the recording file.
+func (r *Recorder) Record(readings map[models.Metric][]ReadingResult) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for metric, results := range readings {
+		for _, result := range results {
+			if err := r.writer.Write(formatRecord(metric, result)); err != nil {
+				return errors.Wrap(err, "failed to write recorded reading")
+			}
+		}
+	}
+
+	r.writer.Flush()
+
+	if err := r.writer.Error(); err != nil {
+		return errors.Wrap(err, "failed to flush recorded readings")
+	}
+
+	if r.gzip != nil {
+		return r.gzip.Flush()
+	}
+
+	return nil
+}
+
+// Close flushes and closes the recording file.
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.writer.Flush()
+
+	if r.gzip != nil {
+		if err := r.gzip.Close(); err != nil {
+			return err
+		}
+	}
+
+	return r.file.Close()
+}
+
+// ReadRecording reads all readings from the recording file by given `path` (see Recorder) ordered by timestamp.
+// Recording, which wasn't properly closed, e.g. due to power loss, is read up to its last flushed reading.
+func ReadRecording(path string) ([]RecordedReading, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var (
+		source io.Reader = file
+		readings []RecordedReading
+	)
+
+	if compressed(path) {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decompress recording")
+		}
+
+		defer gz.Close()
+		source = gz
+	}
+
+	var (
+		reader = csv.NewReader(source)
+	)
+
+	// Trailing optional fields are omitted, so the number of fields varies:
+	reader.FieldsPerRecord = -1
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "failed to read recording on line %d", line)
+		}
+
+		reading, err := parseRecord(record)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid recorded reading on line %d", line)
+		}
+
+		readings = append(readings, reading)
+	}
+
+	sortRecording(readings)
+
+	return readings, nil
+}
+
+func formatRecord(metric models.Metric, result ReadingResult) []string {
+	var (
+		optional = func(v float64) string {
+			if v == 0 {
+				return ""
+			}
+
+			return strconv.FormatFloat(v, 'g', -1, 64)
+		}
+		record = []string{
+			strconv.FormatInt(result.Timestamp.UnixNano(), 10),
+			result.Source,
+			string(metric),
+			strconv.FormatFloat(result.Value, 'g', -1, 64),
+			optional(result.Uncertainty),
+			optional(float64(result.Flags)),
+			optional(float64(result.CalibrationVersion)),
+		}
+	)
+
+	// Trailing empty optional fields are omitted to keep recording compact:
+	for len(record) > 4 && len(record[len(record) - 1]) == 0 {
+		record = record[:len(record) - 1]
+	}
+
+	return record
+}
+
+func parseRecord(record []string) (reading RecordedReading, err error) {
+	var (
+		field = func(i int) string {
+			if i < len(record) {
+				return record[i]
+			}
+
+			return ""
+		}
+	)
+
+	if len(record) < 4 {
+		return reading, errors.New("timestamp, sensor, metric and value are required")
+	}
+
+	nanos, err := strconv.ParseInt(field(0), 10, 64)
+	if err != nil {
+		return reading, errors.Wrap(err, "failed to parse timestamp")
+	}
+
+	reading.Timestamp = time.Unix(0, nanos)
+	reading.Source = field(1)
+	reading.Metric = models.Metric(field(2))
+
+	if reading.Value, err = strconv.ParseFloat(field(3), 64); err != nil {
+		return reading, errors.Wrap(err, "failed to parse value")
+	}
+
+	if v := field(4); len(v) != 0 {
+		if reading.Uncertainty, err = strconv.ParseFloat(v, 64); err != nil {
+			return reading, errors.Wrap(err, "failed to parse uncertainty")
+		}
+	}
+
+	if v := field(5); len(v) != 0 {
+		flags, err := strconv.ParseUint(v, 10, 8)
+		if err != nil {
+			return reading, errors.Wrap(err, "failed to parse quality flags")
+		}
+
+		reading.Flags = QualityFlags(flags)
+	}
+
+	if v := field(6); len(v) != 0 {
+		if reading.CalibrationVersion, err = strconv.Atoi(v); err != nil {
+			return reading, errors.Wrap(err, "failed to parse calibration version")
+		}
+	}
+
+	return reading, nil
+}
+
+func sortRecording(readings []RecordedReading) {
+	sort.SliceStable(readings, func(i, j int) bool {
+		return readings[i].Timestamp.Before(readings[j].Timestamp)
+	})
+}
+
+func compressed(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".gz")
+}
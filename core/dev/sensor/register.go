@@ -8,17 +8,31 @@ import (
 type SensorsRegister map[string]Sensor
 
 // SupportedMetrics aggregates all supported by sensors models.Metric devices.
+//
+// Metrics of the Virtual sensors are only supported when all of their inputs are supported by physical ones.
 func (sr SensorsRegister) SupportedMetrics() models.Metrics {
 	var (
 		availableMetrics = make(map[models.Metric]int)
 	)
 
 	for _, s := range sr {
+		if _, ok := s.(Virtual); ok {
+			continue
+		}
+
 		for _, metric := range s.Metrics() {
 			availableMetrics[metric]++
 		}
 	}
 
+	for _, s := range sr {
+		if v, ok := s.(Virtual); ok && availableAll(availableMetrics, v.Inputs()) {
+			for _, metric := range v.Metrics() {
+				availableMetrics[metric]++
+			}
+		}
+	}
+
 	var (
 		metrics = make([]models.Metric, len(availableMetrics))
 		i       = 0
@@ -28,10 +42,25 @@ func (sr SensorsRegister) SupportedMetrics() models.Metrics {
 		metrics[i] = m
 		i++
 	}
-	
+
 	return metrics
 }
 
+// Capabilities returns measurement Capabilities declared by sensors, mapped by their IDs.
+func (sr SensorsRegister) Capabilities() map[string]Capabilities {
+	var (
+		capabilities = make(map[string]Capabilities)
+	)
+
+	for id, s := range sr {
+		if c := CapabilitiesOf(s); len(c) != 0 {
+			capabilities[id] = c
+		}
+	}
+
+	return capabilities
+}
+
 // Union produces new SensorsRegister combining sensors from original and `sr2`.
 func (sr SensorsRegister) Union(sr2 SensorsRegister) SensorsRegister {
 	sru := SensorsRegister{}
@@ -44,7 +73,7 @@ func (sr SensorsRegister) Union(sr2 SensorsRegister) SensorsRegister {
 		sru[id] = s
 	}
 
-	return sr
+	return sru
 }
 
 // ToList returns slice of all sensor.Sensor devices presented in SensorsRegister.
@@ -72,3 +101,13 @@ func (sr SensorsRegister) Exists(id string) bool {
 	_, is := sr[id]
 	return is
 }
+
+func availableAll(available map[models.Metric]int, metrics []models.Metric) bool {
+	for _, metric := range metrics {
+		if available[metric] == 0 {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,443 @@
+package sensor
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// ErrSensorUnregistered is returned when Sensor is acquired after being removed from the Registry.
+var ErrSensorUnregistered = errors.New("sensor is not registered")
+
+type (
+	// Registry defines concurrency safe pool of the Sensor devices, which also owns their lifecycle:
+	// sensors are initialized on first use, closed once they stay idle for standby timeout, and on removal.
+	//
+	// Registry can be shared by several components, which either iterate over its Snapshot
+	// or Subscribe to be notified on its changes.
+	Registry struct {
+		mutex       sync.RWMutex
+		entries     map[string]*registryEntry
+		standby     time.Duration
+		subscribers map[uint64]RegistryHandler
+		lastSubID   uint64
+	}
+
+	// RegistryEvent defines changes occurred in Registry.
+	RegistryEvent struct {
+		// Added contains sensors added to the Registry.
+		Added []Sensor
+		// Removed contains IDs of the sensors removed from the Registry.
+		Removed []string
+		// Standby contains IDs of the sensors closed after being idle for standby timeout.
+		Standby []string
+	}
+
+	// RegistryHandler defines function, which handles RegistryEvent.
+	RegistryHandler func(event RegistryEvent)
+
+	// registryEntry holds Sensor along with its lifecycle state,
+	// which changes are serialized by the entry's own mutex.
+	registryEntry struct {
+		sync.Mutex
+		sensor      Sensor
+		users       int
+		timer       *time.Timer
+		initialized time.Time
+		removed     bool
+		// deactivated is set when sensor is requested to be closed while being in use.
+		deactivated bool
+	}
+)
+
+// NewRegistry constructs new Registry instance, which closes sensors after they stay idle for `standby` timeout.
+// Zero `standby` keeps sensors active until they are removed.
+func NewRegistry(standby time.Duration) *Registry {
+	return &Registry{
+		entries:     make(map[string]*registryEntry),
+		standby:     standby,
+		subscribers: make(map[uint64]RegistryHandler),
+	}
+}
+
+// Snapshot returns SensorsRegister with sensors registered at the moment of call,
+// which is safe to iterate regardless of the further changes.
+func (r *Registry) Snapshot() SensorsRegister {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var (
+		snapshot = make(SensorsRegister, len(r.entries))
+	)
+
+	for id, e := range r.entries {
+		snapshot[id] = e.sensor
+	}
+
+	return snapshot
+}
+
+// Get returns Sensor registered with given `id`.
+// Sensor can also be referred by its alias or driver ID (see NamesOf), as long as the latter is unambiguous.
+func (r *Registry) Get(id string) (Sensor, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if e, ok := r.entries[id]; ok {
+		return e.sensor, true
+	}
+
+	var (
+		found Sensor
+	)
+
+	for instanceID, e := range r.entries {
+		if Refers(id, instanceID) {
+			if found != nil {
+				return nil, false
+			}
+
+			found = e.sensor
+		}
+	}
+
+	return found, found != nil
+}
+
+// Exists determines whether the Sensor is registered with given `id`.
+func (r *Registry) Exists(id string) bool {
+	_, ok := r.Get(id)
+	return ok
+}
+
+// NotEmpty determines whether Registry contains at least one Sensor.
+func (r *Registry) NotEmpty() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return len(r.entries) > 0
+}
+
+// Add registers given `sensors`, replacing ones already registered with the same IDs.
+func (r *Registry) Add(sensors ...Sensor) {
+	r.Update(sensors, nil)
+}
+
+// Remove unregisters sensors with given `ids` and closes them once they are no longer in use.
+func (r *Registry) Remove(ids ...string) {
+	r.Update(nil, ids)
+}
+
+// Update applies both `added` sensors and removal of sensors with `removed` IDs,
+// notifying subscribers with the changes, which have actually occurred.
+func (r *Registry) Update(added []Sensor, removed []string) {
+	var (
+		event   RegistryEvent
+		retired []*registryEntry
+	)
+
+	r.mutex.Lock()
+
+	for _, id := range removed {
+		if e, ok := r.entries[id]; ok {
+			delete(r.entries, id)
+			retired = append(retired, e)
+			event.Removed = append(event.Removed, id)
+		}
+	}
+
+	for i, s := range added {
+		if e, ok := r.entries[s.ID()]; ok {
+			if e.sensor == s {
+				continue
+			}
+
+			retired = append(retired, e)
+		}
+
+		r.entries[s.ID()] = &registryEntry{
+			sensor: added[i],
+		}
+
+		event.Added = append(event.Added, added[i])
+	}
+
+	r.mutex.Unlock()
+
+	for _, e := range retired {
+		r.retire(e)
+	}
+
+	r.notify(event)
+}
+
+// Subscribe registers `handler` to be notified on Registry changes and returns function to unsubscribe it.
+func (r *Registry) Subscribe(handler RegistryHandler) (unsubscribe func()) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.lastSubID++
+
+	var (
+		id = r.lastSubID
+	)
+
+	r.subscribers[id] = handler
+
+	return func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		delete(r.subscribers, id)
+	}
+}
+
+// Acquire prepares registered Sensor for use by initializing it if needed,
+// and keeps it from being closed on standby until returned `release` function is called.
+// Returns whether sensor initialization was performed.
+func (r *Registry) Acquire(sn Sensor) (release func(), initialized bool, err error) {
+	e, ok := r.entry(sn)
+	if !ok {
+		return nil, false, ErrSensorUnregistered
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	if e.removed {
+		return nil, false, ErrSensorUnregistered
+	}
+
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+
+	if !sn.Active() {
+		if err = sn.Init(); err != nil {
+			return nil, false, err
+		}
+
+		e.initialized = time.Now()
+		initialized = true
+	}
+
+	e.users++
+
+	var (
+		once sync.Once
+	)
+
+	return func() {
+		once.Do(func() {
+			e.Lock()
+			defer e.Unlock()
+
+			r.release(e)
+		})
+	}, initialized, nil
+}
+
+// Probe tries to bring Sensor back to operational state by verifying and re-initializing it from scratch.
+func (r *Registry) Probe(sn Sensor) error {
+	e, ok := r.entry(sn)
+	if !ok {
+		return ErrSensorUnregistered
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	if e.removed {
+		return ErrSensorUnregistered
+	}
+
+	if !sn.Verify() {
+		return errors.New("sensor device verification failed")
+	}
+
+	// Verify may leave sensor partially initialized, so it is re-initialized from scratch:
+	if sn.Active() {
+		if err := sn.Close(); err != nil {
+			shared.Logger.Debug(errors.Wrapf(err, "failed to close connection to '%s' sensor", DisplayName(sn.ID())))
+		}
+	}
+
+	if err := sn.Init(); err != nil {
+		return errors.Wrap(err, "failed to initialize sensor device")
+	}
+
+	e.initialized = time.Now()
+
+	return nil
+}
+
+// Ready determines whether acquired Sensor has warmed up since its initialization (see WarmingUp),
+// so that its readings are valid. Sensors, which doesn't require warm-up, are always ready.
+func (r *Registry) Ready(sn Sensor) bool {
+	w, ok := sn.(WarmingUp)
+	if !ok {
+		return true
+	}
+
+	e, ok := r.entry(sn)
+	if !ok {
+		return false
+	}
+
+	e.Lock()
+	var initialized = e.initialized
+	e.Unlock()
+
+	if initialized.IsZero() || time.Since(initialized) < w.WarmUpDuration() {
+		return false
+	}
+
+	return w.Ready()
+}
+
+// Deactivate closes registered Sensor, which will be re-initialized on the next Acquire.
+// Sensor, which is currently in use, is closed once it is released.
+func (r *Registry) Deactivate(sn Sensor) error {
+	e, ok := r.entry(sn)
+	if !ok {
+		return ErrSensorUnregistered
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	if e.removed {
+		return ErrSensorUnregistered
+	}
+
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+
+	if e.users != 0 {
+		e.deactivated = true
+		return nil
+	}
+
+	if sn.Active() {
+		return sn.Close()
+	}
+
+	return nil
+}
+
+// Close closes all active sensors, leaving them registered.
+func (r *Registry) Close() {
+	for _, sn := range r.Snapshot() {
+		if err := r.Deactivate(sn); err != nil && err != ErrSensorUnregistered {
+			shared.Logger.Error(errors.Wrapf(err, "failed to close connection to '%s' sensor", DisplayName(sn.ID())))
+		}
+	}
+}
+
+func (r *Registry) entry(sn Sensor) (*registryEntry, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	e, ok := r.entries[sn.ID()]
+	if !ok || e.sensor != sn {
+		return nil, false
+	}
+
+	return e, true
+}
+
+// release decrements usages of the entry `e` and schedules its closing on standby,
+// or closes it right away if it was requested while being in use. It must be called with `e` being locked.
+func (r *Registry) release(e *registryEntry) {
+	if e.users > 0 {
+		e.users--
+	}
+
+	if e.users != 0 {
+		return
+	}
+
+	if e.removed || e.deactivated {
+		e.deactivated = false
+		closeSensor(e.sensor)
+		return
+	}
+
+	// Sensors, which warm-up outlasts the standby timeout, are kept active, since each standby would restart it:
+	if WarmUpDuration(e.sensor) > r.standby || r.standby <= 0 {
+		return
+	}
+
+	if e.timer == nil {
+		e.timer = time.AfterFunc(r.standby, func() {
+			r.handleStandby(e)
+		})
+	} else {
+		e.timer.Reset(r.standby)
+	}
+}
+
+// retire closes removed entry `e` right away, unless it is still in use, then it'll be closed on release.
+func (r *Registry) retire(e *registryEntry) {
+	e.Lock()
+	defer e.Unlock()
+
+	e.removed = true
+
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+
+	if e.users == 0 {
+		closeSensor(e.sensor)
+	}
+}
+
+func (r *Registry) handleStandby(e *registryEntry) {
+	e.Lock()
+
+	if e.users != 0 || e.removed || !e.sensor.Active() {
+		e.Unlock()
+		return
+	}
+
+	closeSensor(e.sensor)
+	e.Unlock()
+
+	r.notify(RegistryEvent{
+		Standby: []string{e.sensor.ID()},
+	})
+}
+
+func (r *Registry) notify(event RegistryEvent) {
+	if len(event.Added) == 0 && len(event.Removed) == 0 && len(event.Standby) == 0 {
+		return
+	}
+
+	r.mutex.RLock()
+
+	var (
+		handlers = make([]RegistryHandler, 0, len(r.subscribers))
+	)
+
+	for _, handler := range r.subscribers {
+		handlers = append(handlers, handler)
+	}
+
+	r.mutex.RUnlock()
+
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+func closeSensor(sn Sensor) {
+	if sn.Active() {
+		shared.Execute(sn.Close, fmt.Sprintf("failed to close connection to '%s' sensor", DisplayName(sn.ID())))
+	}
+}
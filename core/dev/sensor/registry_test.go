@@ -0,0 +1,266 @@
+package sensor
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// stubSensor is a Sensor stub, which keeps track of its lifecycle
+// and counts calls made out of order, like Init of the active sensor or Close of the inactive one.
+type stubSensor struct {
+	id         string
+	active     int32
+	violations int32
+}
+
+// warmingSensor is a stubSensor, which declares warm-up duration.
+type warmingSensor struct {
+	*stubSensor
+	warmUp time.Duration
+}
+
+func (s *stubSensor) ID() string                 { return s.id }
+func (s *stubSensor) Harvest(_ *Context)         {}
+func (s *stubSensor) Metrics() []models.Metric   { return nil }
+func (s *stubSensor) Verify() bool               { return true }
+func (s *stubSensor) Active() bool               { return atomic.LoadInt32(&s.active) == 1 }
+
+func (s *stubSensor) Init() error {
+	if !atomic.CompareAndSwapInt32(&s.active, 0, 1) {
+		atomic.AddInt32(&s.violations, 1)
+	}
+
+	return nil
+}
+
+func (s *stubSensor) Close() error {
+	if !atomic.CompareAndSwapInt32(&s.active, 1, 0) {
+		atomic.AddInt32(&s.violations, 1)
+	}
+
+	return nil
+}
+
+func (s warmingSensor) WarmUpDuration() time.Duration { return s.warmUp }
+func (s warmingSensor) Ready() bool                   { return true }
+
+func init() {
+	shared.Logger = logging.MustGetLogger("test")
+}
+
+func TestRegistryConcurrentLifecycle(t *testing.T) {
+	const (
+		workers = 8
+		iterations = 200
+	)
+
+	var (
+		registry = NewRegistry(time.Millisecond)
+		stubs []*stubSensor
+		stubsMutex sync.Mutex
+		events int32
+		wg sync.WaitGroup
+	)
+
+	unsubscribe := registry.Subscribe(func(_ RegistryEvent) {
+		atomic.AddInt32(&events, 1)
+	})
+	defer unsubscribe()
+
+	for i := 0; i < 2; i++ {
+		sn := &stubSensor{id: fmt.Sprintf("MOCK-I2C@1/0x%X", 0x40 + i)}
+		stubs = append(stubs, sn)
+		registry.Add(sn)
+	}
+
+	hammer := func(action func(sn Sensor)) {
+		wg.Add(workers)
+
+		for w := 0; w < workers; w++ {
+			go func(w int) {
+				defer wg.Done()
+
+				for i := 0; i < iterations; i++ {
+					for _, sn := range registry.Snapshot() {
+						action(sn)
+					}
+
+					if i % 16 == w {
+						time.Sleep(time.Millisecond)
+					}
+				}
+			}(w)
+		}
+	}
+
+	hammer(func(sn Sensor) {
+		release, _, err := registry.Acquire(sn)
+		if err != nil {
+			if err != ErrSensorUnregistered {
+				t.Errorf("unexpected acquire error: %v", err)
+			}
+			return
+		}
+
+		registry.Ready(sn)
+		release()
+		release()
+	})
+
+	hammer(func(sn Sensor) {
+		if err := registry.Deactivate(sn); err != nil && err != ErrSensorUnregistered {
+			t.Errorf("unexpected deactivate error: %v", err)
+		}
+	})
+
+	hammer(func(sn Sensor) {
+		if err := registry.Probe(sn); err != nil && err != ErrSensorUnregistered {
+			t.Errorf("unexpected probe error: %v", err)
+		}
+	})
+
+	hammer(func(sn Sensor) {
+		var (
+			replacement = &stubSensor{id: sn.ID()}
+		)
+
+		stubsMutex.Lock()
+		stubs = append(stubs, replacement)
+		stubsMutex.Unlock()
+
+		registry.Update([]Sensor{replacement}, []string{sn.ID()})
+	})
+
+	wg.Wait()
+
+	for id := range registry.Snapshot() {
+		registry.Remove(id)
+	}
+
+	if registry.NotEmpty() {
+		t.Error("expected registry to be empty once all sensors are removed")
+	}
+
+	if atomic.LoadInt32(&events) == 0 {
+		t.Error("expected subscriber to be notified on registry changes")
+	}
+
+	for _, sn := range stubs {
+		if sn.Active() {
+			t.Errorf("expected removed sensor '%s' to be closed", sn.ID())
+		}
+
+		if v := atomic.LoadInt32(&sn.violations); v != 0 {
+			t.Errorf("sensor '%s' was initialized or closed out of order %d times", sn.ID(), v)
+		}
+	}
+}
+
+func TestRegistryStandby(t *testing.T) {
+	const (
+		standby = 10 * time.Millisecond
+	)
+
+	for _, tc := range []struct {
+		name   string
+		sensor Sensor
+		closed bool
+	}{
+		{"without warm-up", &stubSensor{id: "HDC1080@1/0x40"}, true},
+		{"warm-up shorter than standby", warmingSensor{&stubSensor{id: "MAX44009@1/0x4A"}, standby / 2}, true},
+		{"warm-up longer than standby", warmingSensor{&stubSensor{id: "CCS811@1/0x5A"}, time.Minute}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				registry = NewRegistry(standby)
+				standbyIDs = make(chan string, 1)
+			)
+
+			registry.Subscribe(func(event RegistryEvent) {
+				for _, id := range event.Standby {
+					standbyIDs <- id
+				}
+			})
+
+			registry.Add(tc.sensor)
+
+			release, initialized, err := registry.Acquire(tc.sensor)
+			if err != nil || !initialized {
+				t.Fatalf("expected sensor to be initialized on acquire, got error: %v", err)
+			}
+
+			release()
+
+			select {
+			case id := <-standbyIDs:
+				if !tc.closed {
+					t.Fatalf("expected sensor to be kept active, got '%s' in standby", id)
+				}
+			case <-time.After(10 * standby):
+				if tc.closed {
+					t.Fatal("expected sensor to be closed on standby")
+				}
+			}
+
+			if tc.sensor.Active() == tc.closed {
+				t.Errorf("expected sensor active to be %v", !tc.closed)
+			}
+
+			registry.Remove(tc.sensor.ID())
+		})
+	}
+}
+
+func TestRegistryClosesInUseSensorOnRelease(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		action func(r *Registry, sn Sensor)
+	}{
+		{"deactivate", func(r *Registry, sn Sensor) {
+			if err := r.Deactivate(sn); err != nil {
+				t.Fatalf("unexpected deactivate error: %v", err)
+			}
+		}},
+		{"remove", func(r *Registry, sn Sensor) {
+			r.Remove(sn.ID())
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				registry = NewRegistry(0)
+				sn = &stubSensor{id: "SI1145@1/0x60"}
+			)
+
+			registry.Add(sn)
+
+			release, _, err := registry.Acquire(sn)
+			if err != nil {
+				t.Fatalf("unexpected acquire error: %v", err)
+			}
+
+			tc.action(registry, sn)
+
+			if !sn.Active() {
+				t.Fatal("expected sensor in use to stay active")
+			}
+
+			release()
+
+			if sn.Active() {
+				t.Fatal("expected sensor to be closed once released")
+			}
+
+			if atomic.LoadInt32(&sn.violations) != 0 {
+				t.Error("expected sensor to be initialized and closed in order")
+			}
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package sensor
 
 import (
+	"time"
+
 	"github.com/timoth-y/chainmetric-core/models"
 )
 
@@ -22,3 +24,48 @@ type Sensor interface {
 	Close() error
 }
 
+// Timed defines optional interface for Sensor devices, which are able to declare
+// how much time the single Harvest call is expected to take.
+type Timed interface {
+	// ReadDuration returns expected duration of the Sensor reading.
+	ReadDuration() time.Duration
+}
+
+// WarmingUp defines optional interface for Sensor devices, which readings aren't valid right after Init,
+// but only once the device has warmed up.
+type WarmingUp interface {
+	// WarmUpDuration returns how long the Sensor device needs after Init before its readings become valid.
+	WarmUpDuration() time.Duration
+	// Ready determines whether the Sensor device itself reports being ready to provide valid readings.
+	Ready() bool
+}
+
+// WarmUpDuration returns warm-up duration declared by the `sensor`,
+// or zero when Sensor doesn't implement WarmingUp interface.
+func WarmUpDuration(sensor Sensor) time.Duration {
+	if w, ok := sensor.(WarmingUp); ok {
+		return w.WarmUpDuration()
+	}
+
+	return 0
+}
+
+// Virtual defines Sensor, which doesn't have physical device,
+// but derives its readings from readings of the other sensors provided within Context (see Context.Input).
+type Virtual interface {
+	Sensor
+	// Inputs returns models.Metric required for deriving readings of the Virtual sensor.
+	Inputs() []models.Metric
+}
+
+// ReadDuration returns expected reading duration declared by the `sensor`
+// or `fallback` value when Sensor doesn't implement Timed interface.
+func ReadDuration(sensor Sensor, fallback time.Duration) time.Duration {
+	if timed, ok := sensor.(Timed); ok {
+		if d := timed.ReadDuration(); d > 0 {
+			return d
+		}
+	}
+
+	return fallback
+}
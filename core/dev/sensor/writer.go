@@ -2,6 +2,7 @@ package sensor
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/timoth-y/chainmetric-core/models"
 )
@@ -11,9 +12,24 @@ import (
 type MetricWriter struct {
 	metric models.Metric
 	ctx *Context
+	uncertainty float64
+	flags QualityFlags
 }
 
-// Write writes reading results from sensor.Sensor with required type conversation.
+// WithUncertainty returns copy of the MetricWriter, which attaches given `uncertainty` to the written values.
+func (w MetricWriter) WithUncertainty(uncertainty float64) *MetricWriter {
+	w.uncertainty = uncertainty
+	return &w
+}
+
+// WithFlags returns copy of the MetricWriter, which marks written values with given quality `flags`.
+func (w MetricWriter) WithFlags(flags QualityFlags) *MetricWriter {
+	w.flags |= flags
+	return &w
+}
+
+// Write writes reading results from sensor.Sensor with required type conversation
+// and applies Calibration of the sensor if one is provided by the Context.
 func (w *MetricWriter) Write(v interface{}) {
 	var value float64
 
@@ -37,12 +53,41 @@ func (w *MetricWriter) Write(v interface{}) {
 		return
 	}
 
-	if ch, ok := w.ctx.Pipe[w.metric]; ok {
-		ch <- ReadingResult{
-			Source: w.ctx.SensorID,
-			Value:  value,
+	var (
+		result = ReadingResult{
+			Source:      w.ctx.SensorID,
+			Value:       value,
+			Timestamp:   time.Now(),
+			Uncertainty: w.uncertainty,
+			Flags:       w.flags,
+		}
+	)
+
+	// Calibrations might still refer sensor by its alias or previously used driver ID:
+	if w.ctx.Calibrations != nil {
+		for _, name := range NamesOf(w.ctx.SensorID) {
+			if calibration, ok := w.ctx.Calibrations.CalibrationFor(name, w.metric); ok {
+				result.Value = calibration.Apply(value)
+				result.CalibrationVersion = calibration.Version
+				break
+			}
 		}
 	}
+
+	// Declared capability qualifies values, which don't carry their own uncertainty, or exceed measurement range:
+	if capability, ok := w.ctx.Capabilities[w.metric]; ok {
+		if result.Uncertainty == 0 {
+			result.Uncertainty = capability.Accuracy
+		}
+
+		if !capability.InRange(result.Value) {
+			result.Flags |= OutOfRange
+		}
+	}
+
+	if ch, ok := w.ctx.Pipe[w.metric]; ok {
+		ch <- result
+	}
 }
 
 // WriteWithError allows to Write reading results and logged potential error at the same time.
@@ -0,0 +1,132 @@
+package dsp
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+const (
+	testRate = 1600
+	testSamples = 512
+)
+
+// sine samples sum of sine waves of given `frequencies` and `amplitudes` on top of `offset`.
+func sine(offset float64, frequencies []float64, amplitudes []float64) []float64 {
+	var (
+		samples = make([]float64, testSamples)
+	)
+
+	for i := range samples {
+		samples[i] = offset
+
+		for j, f := range frequencies {
+			samples[i] += amplitudes[j] * math.Sin(2 * math.Pi * f * float64(i) / testRate)
+		}
+	}
+
+	return samples
+}
+
+func TestFFTMatchesNaiveDFT(t *testing.T) {
+	var (
+		samples = sine(0.3, []float64{50, 330}, []float64{1, 0.25})[:64]
+		x = make([]complex128, len(samples))
+	)
+
+	for i := range samples {
+		x[i] = complex(samples[i], 0)
+	}
+
+	FFT(x)
+
+	for k := range x {
+		var expected complex128
+
+		for i, s := range samples {
+			expected += complex(s, 0) * cmplx.Exp(complex(0, -2 * math.Pi * float64(k * i) / float64(len(samples))))
+		}
+
+		if cmplx.Abs(x[k] - expected) > 1e-9 {
+			t.Fatalf("bin %d: expected %v, got %v", k, expected, x[k])
+		}
+	}
+}
+
+func TestSpectrumOfSine(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		samples   []float64
+		length    int
+		bin       int
+		magnitude float64
+	}{
+		{"50 Hz", sine(0, []float64{50}, []float64{2}), testSamples / 2 + 1, 16, 1},
+		{"125 Hz", sine(0, []float64{125}, []float64{1}), testSamples / 2 + 1, 40, 0.5},
+		{"zero-padded", sine(0, []float64{50}, []float64{2})[:500], testSamples / 2 + 1, 16, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			magnitudes := Spectrum(tc.samples)
+
+			if len(magnitudes) != tc.length {
+				t.Fatalf("expected %d bins, got %d", tc.length, len(magnitudes))
+			}
+
+			for i := range magnitudes {
+				if magnitudes[i] > magnitudes[tc.bin] {
+					t.Fatalf("expected peak at bin %d, got higher magnitude at bin %d", tc.bin, i)
+				}
+			}
+
+			if math.Abs(magnitudes[tc.bin] - tc.magnitude) > 0.05 {
+				t.Errorf("expected magnitude %v at bin %d, got %v", tc.magnitude, tc.bin, magnitudes[tc.bin])
+			}
+		})
+	}
+}
+
+func TestAnalyzeSine(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		samples     []float64
+		peaks       int
+		rms         float64
+		peak        float64
+		crestFactor float64
+		dominant    []float64
+	}{
+		{"single tone", sine(0, []float64{50}, []float64{2}), 1,
+			2 / math.Sqrt2, 2, math.Sqrt2, []float64{50}},
+		{"gravity offset removed", sine(9.81, []float64{125}, []float64{0.5}), 1,
+			0.5 / math.Sqrt2, 0.5, math.Sqrt2, []float64{125}},
+		{"two tones", sine(0, []float64{50, 200}, []float64{1, 2}), 2,
+			math.Sqrt((1 + 4) / 2.0), 0, 0, []float64{200, 50}},
+		{"silence", make([]float64, testSamples), 1, 0, 0, 0, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			features := Analyze(tc.samples, testRate, tc.peaks)
+
+			if math.Abs(features.RMS - tc.rms) > 1e-3 {
+				t.Errorf("expected RMS %v, got %v", tc.rms, features.RMS)
+			}
+
+			if tc.peak != 0 && math.Abs(features.Peak - tc.peak) > 1e-2 {
+				t.Errorf("expected peak %v, got %v", tc.peak, features.Peak)
+			}
+
+			if tc.crestFactor != 0 && math.Abs(features.CrestFactor - tc.crestFactor) > 1e-2 {
+				t.Errorf("expected crest factor %v, got %v", tc.crestFactor, features.CrestFactor)
+			}
+
+			if len(features.Dominant) != len(tc.dominant) {
+				t.Fatalf("expected dominant frequencies %v, got %v", tc.dominant, features.Dominant)
+			}
+
+			for i := range tc.dominant {
+				if math.Abs(features.Dominant[i] - tc.dominant[i]) > testRate / testSamples {
+					t.Errorf("expected dominant frequencies %v, got %v", tc.dominant, features.Dominant)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package dsp
+
+import (
+	"math"
+	"sort"
+)
+
+// Features defines time and frequency domain features of the sampled signal.
+type Features struct {
+	// RMS is a root mean square of the signal with DC component removed.
+	RMS float64
+	// Peak is the highest absolute deviation of the signal from its DC component.
+	Peak float64
+	// CrestFactor is a ratio of Peak to RMS, showing how impulsive the signal is.
+	CrestFactor float64
+	// Dominant contains frequencies in Hz of the strongest spectrum peaks, ordered by magnitude.
+	Dominant []float64
+}
+
+// Analyze extracts Features from the `samples` sampled with given `rate` in Hz,
+// looking for up to `peaks` dominant frequencies.
+func Analyze(samples []float64, rate float64, peaks int) Features {
+	var (
+		signal = RemoveDC(samples)
+		features = Features{
+			RMS:  RMS(signal),
+			Peak: Peak(signal),
+		}
+	)
+
+	features.CrestFactor = CrestFactor(signal)
+	features.Dominant = DominantFrequencies(signal, rate, peaks)
+
+	return features
+}
+
+// Mean returns arithmetic mean of the `samples`.
+func Mean(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var sum float64
+
+	for _, s := range samples {
+		sum += s
+	}
+
+	return sum / float64(len(samples))
+}
+
+// RemoveDC returns copy of the `samples` with their mean subtracted,
+// which for accelerometer removes static gravity component.
+func RemoveDC(samples []float64) []float64 {
+	var (
+		mean = Mean(samples)
+		signal = make([]float64, len(samples))
+	)
+
+	for i := range samples {
+		signal[i] = samples[i] - mean
+	}
+
+	return signal
+}
+
+// RMS returns root mean square of the `samples`.
+func RMS(samples []float64) float64 {
+	if len(samples) == 0 {
+		return 0
+	}
+
+	var sum float64
+
+	for _, s := range samples {
+		sum += s * s
+	}
+
+	return math.Sqrt(sum / float64(len(samples)))
+}
+
+// Peak returns the highest absolute value of the `samples`.
+func Peak(samples []float64) (peak float64) {
+	for _, s := range samples {
+		peak = math.Max(peak, math.Abs(s))
+	}
+
+	return
+}
+
+// CrestFactor returns ratio of Peak to RMS of the `samples`, or zero for the silent signal.
+func CrestFactor(samples []float64) float64 {
+	var (
+		rms = RMS(samples)
+	)
+
+	if rms == 0 {
+		return 0
+	}
+
+	return Peak(samples) / rms
+}
+
+// DominantFrequencies returns frequencies in Hz of up to `n` strongest local peaks in the spectrum
+// of the `samples` sampled with given `rate`, ordered by magnitude. DC component is ignored.
+func DominantFrequencies(samples []float64, rate float64, n int) []float64 {
+	var (
+		magnitudes = Spectrum(HannWindow(samples))
+		resolution = rate / float64(2 * (len(magnitudes) - 1))
+		bins []int
+	)
+
+	for i := 1; i < len(magnitudes); i++ {
+		var (
+			left = magnitudes[i - 1]
+			right = 0.0
+		)
+
+		if i + 1 < len(magnitudes) {
+			right = magnitudes[i + 1]
+		}
+
+		if magnitudes[i] > 0 && magnitudes[i] >= left && magnitudes[i] > right {
+			bins = append(bins, i)
+		}
+	}
+
+	sort.SliceStable(bins, func(i, j int) bool {
+		return magnitudes[bins[i]] > magnitudes[bins[j]]
+	})
+
+	if len(bins) > n {
+		bins = bins[:n]
+	}
+
+	var (
+		frequencies = make([]float64, len(bins))
+	)
+
+	for i, bin := range bins {
+		frequencies[i] = float64(bin) * resolution
+	}
+
+	return frequencies
+}
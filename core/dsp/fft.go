@@ -0,0 +1,104 @@
+package dsp
+
+import (
+	"math"
+	"math/bits"
+	"math/cmplx"
+)
+
+// HannWindow returns copy of the `samples` tapered with Hann window to reduce spectral leakage.
+func HannWindow(samples []float64) []float64 {
+	var (
+		n = len(samples)
+		windowed = make([]float64, n)
+	)
+
+	if n < 2 {
+		return append(windowed[:0], samples...)
+	}
+
+	for i := range samples {
+		windowed[i] = samples[i] * 0.5 * (1 - math.Cos(2 * math.Pi * float64(i) / float64(n - 1)))
+	}
+
+	return windowed
+}
+
+// Spectrum returns one-sided magnitude spectrum of the real `samples`,
+// which are zero-padded to the next power of two. Result contains N/2+1 bins from DC to Nyquist frequency.
+func Spectrum(samples []float64) []float64 {
+	var (
+		n = nextPowerOfTwo(len(samples))
+		x = make([]complex128, n)
+	)
+
+	for i := range samples {
+		x[i] = complex(samples[i], 0)
+	}
+
+	FFT(x)
+
+	var (
+		magnitudes = make([]float64, n / 2 + 1)
+	)
+
+	for i := range magnitudes {
+		magnitudes[i] = cmplx.Abs(x[i]) / float64(n)
+	}
+
+	return magnitudes
+}
+
+// FFT performs in-place iterative radix-2 Cooley-Tukey fast Fourier transform of `x`,
+// which length must be a power of two.
+func FFT(x []complex128) {
+	var (
+		n = len(x)
+	)
+
+	if n < 2 {
+		return
+	}
+
+	// Bit-reversal permutation:
+	var (
+		shift = 64 - uint(bits.Len(uint(n - 1)))
+	)
+
+	for i := range x {
+		if j := int(bits.Reverse64(uint64(i)) >> shift); j > i {
+			x[i], x[j] = x[j], x[i]
+		}
+	}
+
+	for size := 2; size <= n; size <<= 1 {
+		var (
+			step = cmplx.Exp(complex(0, -2 * math.Pi / float64(size)))
+		)
+
+		for start := 0; start < n; start += size {
+			var (
+				w = complex(1, 0)
+			)
+
+			for k := 0; k < size / 2; k++ {
+				var (
+					even = x[start + k]
+					odd = w * x[start + k + size / 2]
+				)
+
+				x[start + k] = even + odd
+				x[start + k + size / 2] = even - odd
+				w *= step
+			}
+		}
+	}
+}
+
+func nextPowerOfTwo(n int) int {
+	if n <= 1 {
+		return 1
+	}
+
+	return 1 << uint(bits.Len(uint(n - 1)))
+}
@@ -5,34 +5,54 @@ import (
 	"sync"
 
 	"github.com/spf13/viper"
+	"periph.io/x/periph/conn/i2c"
 	"periph.io/x/periph/conn/i2c/i2creg"
 
 	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/drivers/periphery"
 	"github.com/timoth-y/chainmetric-iot/drivers/sensors"
 	"github.com/timoth-y/chainmetric-iot/shared"
 )
 
 // I2CDetectResults stores I2C identified I2C-based peripheral devices.
+// Devices connected via multiplexer are stored under the number of its channel bus (see periphery.I2CMuxChannelBus).
 type I2CDetectResults map[int][]sensor.Sensor
 
-// ScanI2C detects I2C-based devices connected to I2C buses.
-func ScanI2C(addrs []uint16, detector func(addr uint16, bus int) (sensor.Factory, bool)) I2CDetectResults {
+// I2CDetector identifies I2C-based device connected at `addr` on the already opened `bus`.
+type I2CDetector func(bus i2c.Bus, addr uint16) (sensor.Factory, bool)
+
+// ScanI2C detects I2C-based devices connected to I2C buses, including the ones behind I2C multiplexers.
+func ScanI2C(addrs []uint16, detector I2CDetector) I2CDetectResults {
 	var (
 		detected = make(map[int][]sensor.Sensor)
+		mutex    = sync.Mutex{}
 		wg       = sync.WaitGroup{}
 	)
 
 	if viper.GetBool("mocks.debug_env") {
-		detected[1] = []sensor.Sensor{sensors.NewI2CSensorMock(sensors.MOCK_ADDRESS, 1)}
+		detected[1] = []sensor.Sensor{sensor.WithLocation(sensors.NewI2CSensorMock(sensors.MOCK_ADDRESS, 1),
+			periphery.I2CLocationOf(1, sensors.MOCK_ADDRESS).String(),
+		)}
+	}
+
+	// Buses are scanned concurrently, so each one collects devices separately
+	// and then stores them into shared results, even if the scan is interrupted by timeout:
+	collect := func(number int, found []sensor.Sensor) {
+		mutex.Lock()
+		detected[number] = append(detected[number], found...)
+		mutex.Unlock()
 	}
 
 	for _, ref := range i2creg.All() {
-		ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("device.i2c_scan_timeout"))
+		// Multiplexer channels are scanned along with the bus multiplexer is connected to:
+		if ref.Number >= periphery.I2C_MUX_BUS_OFFSET {
+			continue
+		}
+
 		wg.Add(1)
 
-		go func(ctx context.Context, ref *i2creg.Ref) {
+		go func(ref *i2creg.Ref) {
 			defer wg.Done()
-			defer cancel()
 
 			bus, err := ref.Open(); if err != nil {
 				shared.Logger.Error(err)
@@ -40,28 +60,99 @@ func ScanI2C(addrs []uint16, detector func(addr uint16, bus int) (sensor.Factory
 			}
 			defer shared.Execute(bus.Close, "failed to close i2c bus")
 
-			detected[ref.Number] = make([]sensor.Sensor, 0)
+			var (
+				scanned i2c.Bus = bus
+				// Devices connected directly to the bus are reachable from multiplexer channels as well,
+				// thus their addresses, as well as multiplexers ones, are skipped during channels scan:
+				occupied = make(map[uint16]bool)
+			)
 
-			for _, addr := range addrs {
-				if err := bus.Tx(addr, []byte{}, []byte{0x0}); err != nil {
-					continue
+			if viper.GetBool("device.i2c_mux.enabled") {
+				for _, mux := range detectMuxes(bus, ref.Number, detector) {
+					occupied[mux.Addr] = true
 				}
 
-				if sf, ok := detector(addr, ref.Number); ok {
-					detected[ref.Number] = append(detected[ref.Number], sf.Build(ref.Number))
-				}
+				scanned = periphery.IsolateI2CMuxes(bus, ref.Number)
+			}
+
+			collect(ref.Number, scanBus(scanned, ref.Number, addrs, occupied, detector))
 
-				select {
-				case <- ctx.Done():
-					return
-				default:
-					continue
+			for _, mux := range periphery.I2CMuxes(ref.Number) {
+				for _, number := range mux.Channels() {
+					channel, err := i2creg.Open(shared.NtoI2cBusName(number)); if err != nil {
+						shared.Logger.Error(err)
+						continue
+					}
+
+					collect(number, scanBus(channel, number, addrs, occupied, detector))
+					shared.Execute(channel.Close, "failed to close i2c multiplexer channel")
 				}
 			}
-		}(ctx, ref)
+		}(ref)
 	}
 
 	wg.Wait()
 
 	return detected
 }
+
+// scanBus detects devices at `addrs` on the already opened `bus` with given `number`, skipping `occupied` addresses.
+// Addresses responding on the native bus are marked as occupied.
+func scanBus(bus i2c.Bus, number int, addrs []uint16, occupied map[uint16]bool, detector I2CDetector) []sensor.Sensor {
+	var (
+		found = make([]sensor.Sensor, 0)
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("device.i2c_scan_timeout"))
+	defer cancel()
+
+	for _, addr := range addrs {
+		if occupied[addr] {
+			continue
+		}
+
+		if err := bus.Tx(addr, []byte{}, []byte{0x0}); err != nil {
+			continue
+		}
+
+		if number < periphery.I2C_MUX_BUS_OFFSET {
+			occupied[addr] = true
+		}
+
+		// Identical devices can be connected to different buses or multiplexer channels,
+		// thus they are told apart by the location:
+		if sf, ok := detector(bus, addr); ok {
+			found = append(found, sensor.WithLocation(sf.Build(number), periphery.I2CLocationOf(number, addr).String()))
+		}
+
+		select {
+		case <- ctx.Done():
+			return found
+		default:
+			continue
+		}
+	}
+
+	return found
+}
+
+// detectMuxes detects I2C multiplexers connected to the already opened `bus` with given `number`,
+// registers the newly attached ones and unregisters detached ones.
+func detectMuxes(bus i2c.Bus, number int, detector I2CDetector) []*periphery.I2CMux {
+	for addr := uint16(periphery.I2C_MUX_MIN_ADDRESS); addr <= periphery.I2C_MUX_MAX_ADDRESS; addr++ {
+		if err := bus.Tx(addr, []byte{}, []byte{0x0}); err == nil {
+			// Multiplexer addresses are shared with some sensors, which are given the precedence:
+			if _, ok := detector(bus, addr); !ok && periphery.DetectI2CMux(bus, number, addr) {
+				if _, err := periphery.RegisterI2CMux(number, addr); err != nil {
+					shared.Logger.Error(err)
+				}
+
+				continue
+			}
+		}
+
+		periphery.UnregisterI2CMux(number, addr)
+	}
+
+	return periphery.I2CMuxes(number)
+}
@@ -0,0 +1,71 @@
+package io
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	"github.com/spf13/viper"
+
+	"github.com/timoth-y/chainmetric-iot/drivers/periphery/emulated"
+	"github.com/timoth-y/chainmetric-iot/drivers/sensors"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+func TestScanI2COnHotswap(t *testing.T) {
+	shared.Logger = logging.MustGetLogger("test")
+	viper.Set("device.i2c_scan_timeout", time.Second)
+
+	var (
+		bus = emulated.NewBus("hotswap")
+	)
+
+	unregister, err := bus.Register(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unregister()
+
+	for _, tc := range []struct {
+		name     string
+		attach   map[uint16]emulated.Chip
+		detach   []uint16
+		detected []string
+	}{
+		{"empty bus", nil, nil, nil},
+		{"sensors attached", map[uint16]emulated.Chip{
+			sensors.HDC1080_ADDRESS:  emulated.NewHDC1080(),
+			sensors.MAX44009_ADDRESS: emulated.NewMAX44009(),
+		}, nil, []string{"HDC1080@1/0x40", "MAX44009@1/0x4A"}},
+		{"sensor replaced", map[uint16]emulated.Chip{
+			sensors.ADXL345_ADDRESS: emulated.NewADXL345(),
+		}, []uint16{sensors.HDC1080_ADDRESS}, []string{"ADXL345@1/0x53", "MAX44009@1/0x4A"}},
+		{"sensors detached", nil, []uint16{sensors.ADXL345_ADDRESS, sensors.MAX44009_ADDRESS}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for addr, chip := range tc.attach {
+				bus.Attach(addr, chip)
+			}
+
+			for _, addr := range tc.detach {
+				bus.Detach(addr)
+			}
+
+			var (
+				detected []string
+			)
+
+			for _, sn := range ScanI2C(sensors.I2CAddressesRange(), sensors.LocateI2CSensor)[1] {
+				detected = append(detected, sn.ID())
+			}
+
+			sort.Strings(detected)
+
+			if strings.Join(detected, ",") != strings.Join(tc.detected, ",") {
+				t.Errorf("expected %v sensors to be detected, got %v", tc.detected, detected)
+			}
+		})
+	}
+}
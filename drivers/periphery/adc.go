@@ -19,13 +19,16 @@ const (
 
 	ADS1115_DEVICE_ID_REGISTER = 0x0E
 	ADS1115_DEVICE_ID          = 0x80
+
+	// Time of the single conversion at default 128 SPS data rate
+	ADS1115_CONVERSION_TIME = 8 * time.Millisecond
 )
 
 // ADC defines analog to digital peripheral interface.
 type ADC interface {
 	// Init performs ADC driver initialisation.
 	Init() error
-	// Read returns single analog sensor reading value.
+	// Read returns single analog sensor reading value, or NaN if device couldn't be read.
 	Read() float64
 	// RMS aggregates `n` readings from analog sensor and calculates Root Mean Square function.
 	RMS(n int, t *time.Duration) float64
@@ -51,6 +54,7 @@ type ADS1115 struct {
 
 	bias float64
 	convertor func(float64) float64
+	spikeFilter int
 }
 
 // NewADC constructs a new ADC implementation via ADS1115 device driver.
@@ -83,12 +87,14 @@ func (d *ADS1115) Init() (err error) {
 	return nil
 }
 
+// Read returns single converted value from ADC device, or NaN if it couldn't be read.
+// Failed reading must not be mistaken for zero, which would turn into a plausible value after bias is applied.
 func (d *ADS1115) Read() float64 {
 	d.Lock()
 	defer d.Unlock()
 
 	if v, err := d.ADS.ReadRetry(5); err != nil {
-		return 0
+		return math.NaN()
 	} else {
 		return d.convertor(float64(v)) - d.bias
 	}
@@ -97,63 +103,62 @@ func (d *ADS1115) Read() float64 {
 func (d *ADS1115) RMS(n int, t *time.Duration) float64 {
 	var (
 		sum float64
-		i = n
+		results = d.rawSequence(n, t)
 	)
 
-	for i > 0 {
-		if v, err := d.ADS.Read(); err != nil {
-			continue
-		} else {
-			sum +=  math.Pow(float64(v), 2)
-		}
-
-		if t != nil {
-			time.Sleep(*t)
-		}
+	if len(results) == 0 {
+		return math.NaN()
+	}
 
-		i--
+	for _, v := range results {
+		sum += math.Pow(v, 2)
 	}
 
-	return d.convertor(math.Sqrt(sum / float64(n))) - d.bias
+	return d.convertor(math.Sqrt(sum / float64(len(results)))) - d.bias
 }
 
 func (d *ADS1115) Max(n int, t *time.Duration) float64 {
 	results := d.rawSequence(n, t)
 
-	sort.Ints(results)
+	if len(results) == 0 {
+		return math.NaN()
+	}
+
+	sort.Float64s(results)
 
-	return d.convertor(float64(results[len(results) - 1])) - d.bias
+	return d.convertor(results[len(results) - 1]) - d.bias
 }
 
 func (d *ADS1115) Min(n int, t *time.Duration) float64 {
 	results := d.rawSequence(n, t)
 
-	sort.Ints(results)
+	if len(results) == 0 {
+		return math.NaN()
+	}
+
+	sort.Float64s(results)
 
-	return d.convertor(float64(results[0])) - d.bias
+	return d.convertor(results[0]) - d.bias
 }
 
-func (d ADS1115) rawSequence(n int, t *time.Duration) []int {
+// rawSequence reads `n` raw samples from ADC device, skipping failed ones,
+// and applies spike filter to them if such is configured (see WithSpikeFilter).
+func (d ADS1115) rawSequence(n int, t *time.Duration) []float64 {
 	var (
-		i = n
-		results []int
+		results []float64
 	)
 
-	for i > 0 {
-		if v, err := d.ADS.Read(); err != nil {
-			continue
-		} else {
-			results = append(results, int(v))
+	for i := n; i > 0; i-- {
+		if v, err := d.ADS.Read(); err == nil {
+			results = append(results, float64(v))
 		}
 
 		if t != nil {
 			time.Sleep(*t)
 		}
-
-		i--
 	}
 
-	return results
+	return medianFilter(results, d.spikeFilter)
 }
 
 func (d *ADS1115) Verify() bool {
@@ -176,3 +181,39 @@ func (d *ADS1115) Close() error {
 	d.active = false
 	return d.ADS.Close()
 }
+
+// medianFilter replaces each value of `values` with the median of its neighbourhood of given `window` size,
+// which suppresses short spikes without shifting the signal level.
+func medianFilter(values []float64, window int) []float64 {
+	if window < 3 || len(values) < window {
+		return values
+	}
+
+	var (
+		half = window / 2
+		filtered = make([]float64, len(values))
+		neighbourhood = make([]float64, 0, window)
+	)
+
+	for i := range values {
+		var (
+			from = i - half
+			to = i + half + 1
+		)
+
+		if from < 0 {
+			from = 0
+		}
+
+		if to > len(values) {
+			to = len(values)
+		}
+
+		neighbourhood = append(neighbourhood[:0], values[from:to]...)
+		sort.Float64s(neighbourhood)
+
+		filtered[i] = neighbourhood[len(neighbourhood) / 2]
+	}
+
+	return filtered
+}
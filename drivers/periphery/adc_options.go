@@ -38,3 +38,12 @@ func WithI2CMutex(mutex *sync.Mutex) ADCOption {
 		d.Mutex = mutex
 	})
 }
+
+// WithSpikeFilter can be used to suppress spikes in ADC readings
+// by applying median filter of given `window` size to the sampled sequence.
+// Default is 0, which means no filtering.
+func WithSpikeFilter(window int) ADCOption {
+	return ADCOptionFunc(func(d *ADS1115) {
+		d.spikeFilter = window
+	})
+}
@@ -0,0 +1,47 @@
+package emulated
+
+// ADS1115 registers model constants.
+const (
+	ads1115Conversion = 0x00
+	ads1115Config     = 0x01
+	ads1115LoThresh   = 0x02
+	ads1115HiThresh   = 0x03
+
+	ads1115PointerMask = 0x03
+)
+
+// ADS1115 emulates ADS1115 analog-to-digital converter, which conversion is always complete.
+// Its register pointer isn't auto-incremented, so the sequential reads return the same register.
+type ADS1115 struct {
+	Words
+	conversion uint16
+}
+
+// NewADS1115 constructs new ADS1115 chip model with grounded inputs.
+func NewADS1115() *ADS1115 {
+	c := &ADS1115{}
+
+	c.mask = ads1115PointerMask
+	c.fixed = true
+	c.regs[ads1115Config] = 0x8583
+	c.regs[ads1115LoThresh] = 0x8000
+	c.regs[ads1115HiThresh] = 0x7FFF
+
+	c.onWrite = func(reg byte, _ uint16) {
+		// Conversion register is read-only:
+		if reg == ads1115Conversion {
+			c.regs[ads1115Conversion] = c.conversion
+		}
+	}
+
+	return c
+}
+
+// SetConversion sets `raw` conversion result of the chip.
+func (c *ADS1115) SetConversion(raw uint16) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.conversion = raw
+	c.regs[ads1115Conversion] = raw
+}
@@ -0,0 +1,69 @@
+package emulated
+
+import (
+	"math"
+)
+
+// ADXL345 registers model constants.
+const (
+	adxl345DevID      = 0x00
+	adxl345BWRate     = 0x2C
+	adxl345PowerCtl   = 0x2D
+	adxl345DataFormat = 0x31
+	adxl345DataX0     = 0x32
+	adxl345FIFOCtl    = 0x38
+	adxl345FIFOStatus = 0x39
+
+	adxl345ID          = 0xE5
+	adxl345Measure     = 0x08
+	adxl345FIFOMode    = 0xC0
+	adxl345FIFOSize    = 32
+	adxl345ScaleFactor = 0.0039
+)
+
+// ADXL345 emulates ADXL345 3-axis accelerometer, which FIFO is always full while it is enabled.
+type ADXL345 struct {
+	Registers
+}
+
+// NewADXL345 constructs new ADXL345 chip model at rest, experiencing only gravity along Z axis.
+func NewADXL345() *ADXL345 {
+	c := &ADXL345{}
+
+	c.regs[adxl345DevID] = adxl345ID
+	c.regs[adxl345BWRate] = 0x0A
+	c.SetAcceleration(0, 0, 1)
+
+	c.onRead = func(reg byte) {
+		if reg != adxl345FIFOStatus {
+			return
+		}
+
+		if c.regs[adxl345FIFOCtl] & adxl345FIFOMode != 0 && c.regs[adxl345PowerCtl] & adxl345Measure != 0 {
+			c.regs[adxl345FIFOStatus] = adxl345FIFOSize
+		} else {
+			c.regs[adxl345FIFOStatus] = 0
+		}
+	}
+
+	return c
+}
+
+// SetAcceleration sets acceleration along `x`, `y` and `z` axes as multiplications of G.
+func (c *ADXL345) SetAcceleration(x, y, z float64) {
+	var (
+		data = make([]byte, 0, 6)
+	)
+
+	for _, g := range []float64{x, y, z} {
+		raw := int16(math.Round(g / adxl345ScaleFactor))
+		data = append(data, byte(raw), byte(raw >> 8))
+	}
+
+	c.Set(adxl345DataX0, data...)
+}
+
+// Measuring determines whether measurement has been enabled on the chip.
+func (c *ADXL345) Measuring() bool {
+	return c.Get(adxl345PowerCtl) & adxl345Measure != 0
+}
@@ -0,0 +1,91 @@
+package emulated
+
+import (
+	"encoding/binary"
+)
+
+// BMx280 registers model constants.
+const (
+	bmx280Calibration         = 0x88
+	bmx280ChipID              = 0xD0
+	bmx280HumidityCalibration = 0xE1
+	bmx280Status              = 0xF3
+	bmx280Data                = 0xF7
+
+	bmp280ID = 0x58
+	bme280ID = 0x60
+)
+
+var (
+	// bmx280CalibrationData are compensation parameters from the BMP280 datasheet example (section 8.2).
+	bmx280CalibrationData = []int{27504, 26435, -1000, 36477, -10685, 3024, 2855, 140, -7, 15500, -14600, 6000}
+)
+
+// BMX280 emulates BMP280 barometer or BME280 barometer and humidity sensor,
+// which measurements are always complete and are compensated with the datasheet example calibration.
+type BMX280 struct {
+	Registers
+}
+
+// NewBMP280 constructs new BMP280 chip model measuring 25.08°C and 100653 Pa.
+func NewBMP280() *BMX280 {
+	return newBMX280(bmp280ID)
+}
+
+// NewBME280 constructs new BME280 chip model measuring 25.08°C, 100653 Pa and 0% of relative humidity.
+func NewBME280() *BMX280 {
+	return newBMX280(bme280ID)
+}
+
+func newBMX280(id byte) *BMX280 {
+	var (
+		c = &BMX280{}
+		calibration = make([]byte, 2 * len(bmx280CalibrationData))
+	)
+
+	for i, value := range bmx280CalibrationData {
+		binary.LittleEndian.PutUint16(calibration[2 * i:], uint16(value))
+	}
+
+	c.regs[bmx280ChipID] = id
+	c.Set(bmx280Calibration, calibration...)
+
+	if id == bme280ID {
+		// H1 goes right after the pressure calibration, H2 to H6 are stored separately:
+		c.Set(bmx280Calibration + byte(len(calibration)) + 1, 75)
+		c.Set(bmx280HumidityCalibration, 0x6A, 0x01, 0x00, 0x13, 0x29, 0x03, 0x1E)
+	}
+
+	c.SetRaw(519888, 415148, 0)
+
+	return c
+}
+
+// Tx performs transaction with the chip. Unlike reads, writes aren't auto-incremented,
+// but rather consist of register address and value pairs.
+func (c *BMX280) Tx(w, r []byte) error {
+	if len(w) > 1 {
+		c.mutex.Lock()
+
+		for i := 0; i + 1 < len(w); i += 2 {
+			c.regs[w[i]] = w[i + 1]
+		}
+
+		c.regs[bmx280Status] = 0
+		c.mutex.Unlock()
+
+		w = nil
+	}
+
+	return c.Registers.Tx(w, r)
+}
+
+// SetRaw sets uncompensated `temperature`, `pressure` and `humidity` readings of the chip,
+// the first two of which are 20-bit values.
+func (c *BMX280) SetRaw(temperature, pressure uint32, humidity uint16) {
+	c.Set(bmx280Data,
+		byte(pressure >> 12), byte(pressure >> 4), byte(pressure << 4),
+		byte(temperature >> 12), byte(temperature >> 4), byte(temperature << 4),
+		byte(humidity >> 8), byte(humidity),
+	)
+}
@@ -0,0 +1,141 @@
+// Package emulated provides in-memory I2C bus along with register-level models of the chips
+// used by the device, so that drivers can be exercised without the actual hardware.
+package emulated
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+var (
+	// ErrNoAck is returned on transaction with address, at which no chip is attached.
+	ErrNoAck = errors.New("emulated i2c: no device acknowledged the address")
+)
+
+type (
+	// Chip defines register-level model of the I2C device attached to the emulated Bus.
+	Chip interface {
+		// Tx performs transaction with the chip: writes `w` bytes first, then reads into `r`.
+		Tx(w, r []byte) error
+	}
+
+	// Bus implements in-memory i2c.BusCloser, which transactions are served by chips attached to it.
+	Bus struct {
+		mutex  sync.Mutex
+		name   string
+		chips  map[uint16]Chip
+		faults map[uint16]error
+		txs    map[uint16]int
+	}
+)
+
+// NewBus constructs new emulated Bus with given `name`.
+func NewBus(name string) *Bus {
+	return &Bus{
+		name:   name,
+		chips:  make(map[uint16]Chip),
+		faults: make(map[uint16]error),
+		txs:    make(map[uint16]int),
+	}
+}
+
+// Register registers the Bus in i2creg under given `number`, so that periphery.I2C and third-party drivers
+// opening buses by number (see shared.NtoI2cBusName) would be connected to it.
+// Returned function unregisters the Bus.
+func (b *Bus) Register(number int) (func(), error) {
+	if err := i2creg.Register(b.name, []string{shared.NtoI2cBusName(number)}, number,
+		func() (i2c.BusCloser, error) {
+			return b, nil
+		},
+	); err != nil {
+		return nil, errors.Wrapf(err, "failed to register emulated bus '%s'", b.name)
+	}
+
+	return func() {
+		_ = i2creg.Unregister(b.name)
+	}, nil
+}
+
+// Attach attaches `chip` to the Bus at `addr`, which emulates its hot plugging.
+func (b *Bus) Attach(addr uint16, chip Chip) *Bus {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.chips[addr] = chip
+
+	return b
+}
+
+// Detach detaches chip at `addr` from the Bus, so that it would no longer acknowledge transactions.
+func (b *Bus) Detach(addr uint16) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	delete(b.chips, addr)
+}
+
+// Fail makes transactions with the chip at `addr` fail with given `err`, until it is cleared with nil.
+func (b *Bus) Fail(addr uint16, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if err == nil {
+		delete(b.faults, addr)
+		return
+	}
+
+	b.faults[addr] = err
+}
+
+// Transactions returns number of transactions addressed to `addr`, including the failed ones.
+func (b *Bus) Transactions(addr uint16) int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.txs[addr]
+}
+
+// Tx does a transaction with the chip attached at `addr`.
+func (b *Bus) Tx(addr uint16, w, r []byte) error {
+	b.mutex.Lock()
+
+	b.txs[addr]++
+
+	var (
+		chip, ok = b.chips[addr]
+		fault = b.faults[addr]
+	)
+
+	b.mutex.Unlock()
+
+	if fault != nil {
+		return fault
+	}
+
+	if !ok {
+		return errors.Wrapf(ErrNoAck, "0x%02X", addr)
+	}
+
+	return chip.Tx(w, r)
+}
+
+// SetSpeed does nothing, since emulated transactions are instant.
+func (b *Bus) SetSpeed(_ physic.Frequency) error {
+	return nil
+}
+
+func (b *Bus) String() string {
+	return fmt.Sprintf("emulated(%s)", b.name)
+}
+
+// Close does nothing, since the Bus is shared by all the drivers opening it.
+func (b *Bus) Close() error {
+	return nil
+}
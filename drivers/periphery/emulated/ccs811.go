@@ -0,0 +1,98 @@
+package emulated
+
+import (
+	"bytes"
+)
+
+// CCS811 registers model constants.
+const (
+	ccs811Status        = 0x00
+	ccs811MeasMode      = 0x01
+	ccs811AlgResultData = 0x02
+	ccs811HWID          = 0x20
+	ccs811ErrorID       = 0xE0
+	ccs811AppStart      = 0xF4
+	ccs811SWReset       = 0xFF
+
+	ccs811ID           = 0x81
+	ccs811ErrorBit     = 0x01
+	ccs811DataReadyBit = 0x08
+	ccs811AppValidBit  = 0x10
+	ccs811FWModeBit    = 0x80
+	ccs811DriveMode    = 0x70
+)
+
+var (
+	ccs811ResetSequence = []byte{0x11, 0xE5, 0x72, 0x8A}
+)
+
+// CCS811 emulates CCS811 air quality sensor, which boots into bootloader mode
+// and has data ready whenever application is started with non-idle drive mode.
+type CCS811 struct {
+	Registers
+	errorID byte
+}
+
+// NewCCS811 constructs new CCS811 chip model measuring 400 ppm of eCO2 and 0 ppb of TVOC.
+func NewCCS811() *CCS811 {
+	c := &CCS811{}
+
+	c.regs[ccs811Status] = ccs811AppValidBit
+	c.regs[ccs811HWID] = ccs811ID
+	c.SetAirQuality(400, 0)
+
+	c.onSelect = func(reg byte) {
+		if reg == ccs811AppStart {
+			c.regs[ccs811Status] |= ccs811FWModeBit
+		}
+	}
+
+	c.onRead = func(reg byte) {
+		if reg != ccs811Status {
+			return
+		}
+
+		c.regs[ccs811Status] &^= ccs811DataReadyBit | ccs811ErrorBit
+
+		if c.regs[ccs811Status] & ccs811FWModeBit != 0 && c.regs[ccs811MeasMode] & ccs811DriveMode != 0 {
+			c.regs[ccs811Status] |= ccs811DataReadyBit
+		}
+
+		if c.errorID != 0 {
+			c.regs[ccs811Status] |= ccs811ErrorBit
+			c.regs[ccs811ErrorID] = c.errorID
+		}
+	}
+
+	return c
+}
+
+// Tx performs transaction with the chip, handling software reset sequence written to its mailbox.
+func (c *CCS811) Tx(w, r []byte) error {
+	if len(w) > 1 && w[0] == ccs811SWReset {
+		c.mutex.Lock()
+		defer c.mutex.Unlock()
+
+		if bytes.Equal(w[1:], ccs811ResetSequence) {
+			c.regs[ccs811Status] = ccs811AppValidBit
+			c.regs[ccs811MeasMode] = 0
+		}
+
+		return nil
+	}
+
+	return c.Registers.Tx(w, r)
+}
+
+// SetAirQuality sets `eCO2` in ppm and `tvoc` in ppb concentrations measured by the chip.
+func (c *CCS811) SetAirQuality(eCO2, tvoc uint16) {
+	c.Set(ccs811AlgResultData, byte(eCO2 >> 8), byte(eCO2), byte(tvoc >> 8), byte(tvoc))
+}
+
+// SetError sets error reported by the chip by its `errorID` bits, or clears it with zero.
+func (c *CCS811) SetError(errorID byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.errorID = errorID
+}
@@ -0,0 +1,37 @@
+package emulated
+
+import (
+	"math"
+)
+
+// HDC1080 registers model constants.
+const (
+	hdc1080Temperature    = 0x00
+	hdc1080Humidity       = 0x01
+	hdc1080Configuration  = 0x02
+	hdc1080ManufacturerID = 0xFE
+	hdc1080DeviceID       = 0xFF
+)
+
+// HDC1080 emulates HDC1080 temperature and humidity sensor.
+type HDC1080 struct {
+	Words
+}
+
+// NewHDC1080 constructs new HDC1080 chip model measuring 25°C and 50% of relative humidity.
+func NewHDC1080() *HDC1080 {
+	c := &HDC1080{}
+
+	c.regs[hdc1080Configuration] = 0x1000
+	c.regs[hdc1080ManufacturerID] = 0x5449
+	c.regs[hdc1080DeviceID] = 0x1050
+	c.SetClimate(25, 50)
+
+	return c
+}
+
+// SetClimate sets `temperature` in °C and `humidity` in % of relative humidity measured by the chip.
+func (c *HDC1080) SetClimate(temperature, humidity float64) {
+	c.Set(hdc1080Temperature, uint16(math.Round((temperature + 40) / 165 * 65536)))
+	c.Set(hdc1080Humidity, uint16(math.Round(humidity / 100 * 65536)))
+}
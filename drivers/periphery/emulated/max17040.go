@@ -0,0 +1,39 @@
+package emulated
+
+import (
+	"math"
+)
+
+// MAX17040 registers model constants.
+const (
+	max17040VCell   = 0x02
+	max17040SOC     = 0x04
+	max17040Mode    = 0x06
+	max17040Version = 0x08
+	max17040Config  = 0x0C
+	max17040Command = 0xFE
+
+	max17040VoltsPerLSB = 0.00125
+)
+
+// MAX17040 emulates MAX17040 fuel gauge of the UPS shield.
+type MAX17040 struct {
+	Words
+}
+
+// NewMAX17040 constructs new MAX17040 chip model of the fully charged battery.
+func NewMAX17040() *MAX17040 {
+	c := &MAX17040{}
+
+	c.regs[max17040Version] = 0x0003
+	c.regs[max17040Config] = 0x971C
+	c.SetBattery(4.2, 100)
+
+	return c
+}
+
+// SetBattery sets battery `voltage` in volts and state of charge `level` in % measured by the chip.
+func (c *MAX17040) SetBattery(voltage, level float64) {
+	c.Set(max17040VCell, uint16(math.Round(voltage / max17040VoltsPerLSB)) << 4)
+	c.Set(max17040SOC, uint16(math.Round(level * 256)))
+}
@@ -0,0 +1,51 @@
+package emulated
+
+import (
+	"math"
+)
+
+// MAX44009 registers model constants.
+const (
+	max44009LuxHigh = 0x03
+	max44009LuxLow  = 0x04
+	max44009ID      = 0x0F
+
+	max44009IDValue      = 0x3F
+	max44009Resolution   = 0.045
+	max44009MaxExponent  = 14
+	max44009MaxMantissa  = 0xFF
+)
+
+// MAX44009 emulates MAX44009 ambient light sensor.
+type MAX44009 struct {
+	Registers
+}
+
+// NewMAX44009 constructs new MAX44009 chip model measuring darkness.
+func NewMAX44009() *MAX44009 {
+	c := &MAX44009{}
+
+	// Undocumented register, which value is used by driver to identify the chip:
+	c.regs[max44009ID] = max44009IDValue
+
+	return c
+}
+
+// SetLux sets luminosity in lux measured by the chip, which is encoded with the least exponent possible.
+func (c *MAX44009) SetLux(lux float64) {
+	var (
+		exponent int
+		mantissa = math.Round(lux / max44009Resolution)
+	)
+
+	for mantissa > max44009MaxMantissa && exponent < max44009MaxExponent {
+		exponent++
+		mantissa = math.Round(lux / max44009Resolution / math.Pow(2, float64(exponent)))
+	}
+
+	var (
+		m = byte(math.Min(mantissa, max44009MaxMantissa))
+	)
+
+	c.Set(max44009LuxHigh, byte(exponent) << 4 | m >> 4, m & 0x0F)
+}
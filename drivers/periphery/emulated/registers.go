@@ -0,0 +1,170 @@
+package emulated
+
+import (
+	"sync"
+)
+
+type (
+	// Registers emulates chip with 8-bit registers, which pointer is set by the first written byte
+	// and is auto-incremented on each following byte written or read, as most of the I2C chips do.
+	Registers struct {
+		mutex   sync.Mutex
+		regs    [256]byte
+		pointer byte
+
+		// onSelect is called when register pointer is set by the write.
+		onSelect func(reg byte)
+		// onWrite is called after `value` is written to the `reg` register.
+		onWrite func(reg, value byte)
+		// onRead is called prior to `reg` register being read, so that its value could be updated.
+		onRead func(reg byte)
+	}
+
+	// Words emulates chip with 16-bit registers, which pointer is set by the first written byte,
+	// followed by the big endian words to write, and is auto-incremented after each word written or read,
+	// unless it is fixed.
+	Words struct {
+		mutex   sync.Mutex
+		regs    [256]uint16
+		pointer byte
+
+		// mask limits pointer to the implemented registers.
+		mask byte
+		// fixed keeps pointer at the selected register, instead of auto-incrementing it.
+		fixed bool
+		// onSelect is called when register pointer is set by the write.
+		onSelect func(reg byte)
+		// onWrite is called after `value` is written to the `reg` register.
+		onWrite func(reg byte, value uint16)
+		// onRead is called prior to `reg` register being read, so that its value could be updated.
+		onRead func(reg byte)
+	}
+)
+
+// Tx performs transaction with the chip: selects register and writes the rest of `w` bytes, then reads into `r`.
+func (c *Registers) Tx(w, r []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(w) > 0 {
+		c.pointer = w[0]
+
+		if c.onSelect != nil {
+			c.onSelect(c.pointer)
+		}
+
+		for _, value := range w[1:] {
+			c.regs[c.pointer] = value
+
+			if c.onWrite != nil {
+				c.onWrite(c.pointer, value)
+			}
+
+			c.pointer++
+		}
+	}
+
+	for i := range r {
+		if c.onRead != nil {
+			c.onRead(c.pointer)
+		}
+
+		r[i] = c.regs[c.pointer]
+		c.pointer++
+	}
+
+	return nil
+}
+
+// Set sets `values` of the consecutive registers starting from `reg`, without triggering chip behaviour.
+func (c *Registers) Set(reg byte, values ...byte) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for i := range values {
+		c.regs[reg + byte(i)] = values[i]
+	}
+}
+
+// Get returns value of the `reg` register.
+func (c *Registers) Get(reg byte) byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.regs[reg]
+}
+
+// Tx performs transaction with the chip: selects register and writes the rest of `w` bytes, then reads into `r`.
+func (c *Words) Tx(w, r []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if len(w) > 0 {
+		c.pointer = c.masked(w[0])
+
+		if c.onSelect != nil {
+			c.onSelect(c.pointer)
+		}
+
+		for i := 1; i + 1 < len(w); i += 2 {
+			var (
+				value = uint16(w[i]) << 8 | uint16(w[i + 1])
+			)
+
+			c.regs[c.pointer] = value
+
+			if c.onWrite != nil {
+				c.onWrite(c.pointer, value)
+			}
+
+			c.pointer = c.next(c.pointer)
+		}
+	}
+
+	for i := range r {
+		if i % 2 == 0 && c.onRead != nil {
+			c.onRead(c.pointer)
+		}
+
+		if i % 2 == 0 {
+			r[i] = byte(c.regs[c.pointer] >> 8)
+		} else {
+			r[i] = byte(c.regs[c.pointer])
+			c.pointer = c.next(c.pointer)
+		}
+	}
+
+	return nil
+}
+
+// Set sets `value` of the `reg` register, without triggering chip behaviour.
+func (c *Words) Set(reg byte, value uint16) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.regs[c.masked(reg)] = value
+}
+
+// Get returns value of the `reg` register.
+func (c *Words) Get(reg byte) uint16 {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.regs[c.masked(reg)]
+}
+
+func (c *Words) masked(reg byte) byte {
+	if c.mask == 0 {
+		return reg
+	}
+
+	return reg & c.mask
+}
+
+func (c *Words) next(reg byte) byte {
+	if c.fixed {
+		return reg
+	}
+
+	return c.masked(reg + 1)
+}
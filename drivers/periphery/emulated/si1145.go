@@ -0,0 +1,68 @@
+package emulated
+
+// SI1145 registers model constants.
+const (
+	si1145PartID      = 0x00
+	si1145SeqID       = 0x02
+	si1145ParamWR     = 0x17
+	si1145Command     = 0x18
+	si1145ALSVisData0 = 0x22
+	si1145ALSIRData0  = 0x24
+	si1145PS1Data0    = 0x26
+	si1145UVIndex0    = 0x2C
+	si1145ParamRD     = 0x2E
+
+	si1145ID         = 0x45
+	si1145Seq        = 0x08
+	si1145ParamQuery = 0x80
+	si1145ParamSet   = 0xA0
+	si1145ParamMask  = 0x1F
+	si1145CmdMask    = 0xE0
+)
+
+// SI1145 emulates SI1145 UV index, visible, IR light and proximity sensor,
+// including its parameters RAM accessed via command register.
+type SI1145 struct {
+	Registers
+	params [32]byte
+}
+
+// NewSI1145 constructs new SI1145 chip model measuring nothing.
+func NewSI1145() *SI1145 {
+	c := &SI1145{}
+
+	c.regs[si1145PartID] = si1145ID
+	c.regs[si1145SeqID] = si1145Seq
+
+	c.onWrite = func(reg, value byte) {
+		if reg != si1145Command {
+			return
+		}
+
+		switch value & si1145CmdMask {
+		case si1145ParamSet:
+			c.params[value & si1145ParamMask] = c.regs[si1145ParamWR]
+			c.regs[si1145ParamRD] = c.params[value & si1145ParamMask]
+		case si1145ParamQuery:
+			c.regs[si1145ParamRD] = c.params[value & si1145ParamMask]
+		}
+	}
+
+	return c
+}
+
+// SetLight sets `uv` index multiplied by 100, `visible` and `ir` light levels and `proximity` measured by the chip.
+func (c *SI1145) SetLight(uv, visible, ir, proximity uint16) {
+	c.Set(si1145UVIndex0, byte(uv), byte(uv >> 8))
+	c.Set(si1145ALSVisData0, byte(visible), byte(visible >> 8))
+	c.Set(si1145ALSIRData0, byte(ir), byte(ir >> 8))
+	c.Set(si1145PS1Data0, byte(proximity), byte(proximity >> 8))
+}
+
+// Param returns value of the parameter stored in chip's RAM at `addr`.
+func (c *SI1145) Param(addr byte) byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.params[addr & si1145ParamMask]
+}
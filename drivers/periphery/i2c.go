@@ -16,6 +16,7 @@ type I2C struct {
 	i2c.Dev
 	*sync.Mutex
 	name   string
+	number int
 	bus    i2c.BusCloser
 	active bool
 }
@@ -28,6 +29,7 @@ func NewI2C(addr uint16, bus int, options ...I2COption) *I2C {
 		},
 		Mutex: &sync.Mutex{},
 		name: shared.NtoI2cBusName(bus),
+		number: bus,
 	}
 
 	for i := range options {
@@ -44,6 +46,13 @@ func (i *I2C) Init() (err error) {
 	}
 
 	i.Bus = i.bus
+
+	// Devices connected directly to the native bus are reachable from the multiplexers channels as well,
+	// so its transactions are serialized with channels ones and performed with all of them deselected:
+	if i.number < I2C_MUX_BUS_OFFSET && len(I2CMuxes(i.number)) != 0 {
+		i.Bus = IsolateI2CMuxes(i.bus, i.number)
+	}
+
 	i.active = true
 
 	return
@@ -208,6 +217,11 @@ func (i *I2C) Verify() bool {
 	return true
 }
 
+// Location returns location of the I2C device, which might be connected via multiplexer.
+func (i *I2C) Location() I2CLocation {
+	return I2CLocationOf(i.number, i.Addr)
+}
+
 // Active checks whether the I2C device is connected and active.
 func (i *I2C) Active() bool {
 	return i.active
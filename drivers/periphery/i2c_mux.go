@@ -0,0 +1,360 @@
+package periphery
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"periph.io/x/periph/conn/i2c"
+	"periph.io/x/periph/conn/i2c/i2creg"
+	"periph.io/x/periph/conn/physic"
+
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// TCA9548A (PCA9548) I2C multiplexer constants.
+const (
+	I2C_MUX_MIN_ADDRESS = 0x70
+	I2C_MUX_MAX_ADDRESS = 0x77
+	I2C_MUX_CHANNELS    = 8
+
+	// I2C_MUX_BUS_OFFSET is a number starting from which multiplexer channels are numbered as I2C buses.
+	I2C_MUX_BUS_OFFSET = 100
+)
+
+type (
+	// I2CMux provides driver for TCA9548A (PCA9548) I2C multiplexer,
+	// which downstream channels are registered as separate I2C buses, numbered with I2CMuxChannelBus.
+	I2CMux struct {
+		Bus  int
+		Addr uint16
+
+		// selected is a channels mask currently set on the multiplexer, or -1 when it is unknown.
+		selected int
+	}
+
+	// I2CLocation defines where I2C device is connected: at Addr on the Bus,
+	// or, when Mux is set, at Addr on the Channel of multiplexer connected to the Bus.
+	I2CLocation struct {
+		Bus     int
+		Mux     uint16
+		Channel int
+		Addr    uint16
+	}
+
+	// i2cMuxedBus holds multiplexers connected to the same I2C bus,
+	// which transactions are serialized, since channel must be selected prior to each of them.
+	i2cMuxedBus struct {
+		sync.Mutex
+		muxes map[uint16]*I2CMux
+	}
+
+	// i2cMuxChannel implements i2c.BusCloser for the downstream channel of I2CMux.
+	i2cMuxChannel struct {
+		i2c.BusCloser
+		mux     *I2CMux
+		channel int
+	}
+
+	// i2cMuxIsolated implements i2c.Bus, which transactions are performed with all multiplexer channels deselected.
+	i2cMuxIsolated struct {
+		i2c.Bus
+		number int
+	}
+)
+
+var (
+	i2cMuxesMutex = sync.Mutex{}
+	i2cMuxedBuses = make(map[int]*i2cMuxedBus)
+)
+
+// DetectI2CMux determines whether I2C multiplexer is connected at `addr` on the already opened `bus`
+// with given `number`, by writing channels mask to its control register and reading it back.
+// All channels are left deselected afterwards.
+func DetectI2CMux(bus i2c.Bus, number int, addr uint16) bool {
+	if addr < I2C_MUX_MIN_ADDRESS || addr > I2C_MUX_MAX_ADDRESS {
+		return false
+	}
+
+	mb := muxedBus(number)
+
+	mb.Lock()
+	defer mb.Unlock()
+
+	if mux, ok := mb.muxes[addr]; ok {
+		mux.selected = -1
+	}
+
+	for _, mask := range []byte{0xA5, 0x00} {
+		var (
+			b = make([]byte, 1)
+		)
+
+		if err := bus.Tx(addr, []byte{mask}, nil); err != nil {
+			return false
+		}
+
+		if err := bus.Tx(addr, nil, b); err != nil || b[0] != mask {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RegisterI2CMux registers I2C multiplexer connected at `addr` on the `bus`, along with its downstream channels,
+// which are then can be opened via i2creg as regular I2C buses. It is a no-op if multiplexer is already registered.
+func RegisterI2CMux(bus int, addr uint16) (*I2CMux, error) {
+	i2cMuxesMutex.Lock()
+	defer i2cMuxesMutex.Unlock()
+
+	mb, ok := i2cMuxedBuses[bus]; if !ok {
+		mb = &i2cMuxedBus{muxes: make(map[uint16]*I2CMux)}
+		i2cMuxedBuses[bus] = mb
+	}
+
+	mb.Lock()
+	defer mb.Unlock()
+
+	if mux, ok := mb.muxes[addr]; ok {
+		return mux, nil
+	}
+
+	mux := &I2CMux{
+		Bus:      bus,
+		Addr:     addr,
+		selected: -1,
+	}
+
+	for ch := 0; ch < I2C_MUX_CHANNELS; ch++ {
+		var (
+			channel = ch
+			number  = I2CMuxChannelBus(bus, addr, ch)
+		)
+
+		// Channel bus is aliased with the conventional name of its number,
+		// so that drivers could open it the same way as native buses (see shared.NtoI2cBusName):
+		if err := i2creg.Register(mux.channelName(ch), []string{shared.NtoI2cBusName(number)}, number,
+			func() (i2c.BusCloser, error) {
+				return mux.open(channel)
+			},
+		); err != nil {
+			mux.unregister(ch)
+			return nil, errors.Wrapf(err, "failed to register channel %d of I2C multiplexer at 0x%02X", ch, addr)
+		}
+	}
+
+	mb.muxes[addr] = mux
+
+	return mux, nil
+}
+
+// UnregisterI2CMux unregisters I2C multiplexer connected at `addr` on the `bus` along with its downstream channels.
+func UnregisterI2CMux(bus int, addr uint16) {
+	i2cMuxesMutex.Lock()
+	defer i2cMuxesMutex.Unlock()
+
+	mb, ok := i2cMuxedBuses[bus]; if !ok {
+		return
+	}
+
+	mb.Lock()
+	defer mb.Unlock()
+
+	if mux, ok := mb.muxes[addr]; ok {
+		mux.unregister(I2C_MUX_CHANNELS)
+		delete(mb.muxes, addr)
+	}
+}
+
+// I2CMuxes returns multiplexers registered on the `bus` sorted by their addresses.
+func I2CMuxes(bus int) []*I2CMux {
+	i2cMuxesMutex.Lock()
+	defer i2cMuxesMutex.Unlock()
+
+	var (
+		muxes []*I2CMux
+	)
+
+	if mb, ok := i2cMuxedBuses[bus]; ok {
+		for _, mux := range mb.muxes {
+			muxes = append(muxes, mux)
+		}
+	}
+
+	sort.Slice(muxes, func(i, j int) bool {
+		return muxes[i].Addr < muxes[j].Addr
+	})
+
+	return muxes
+}
+
+// IsolateI2CMuxes wraps the already opened `bus` with given `number`, so that its transactions
+// would be performed with all channels of the multiplexers connected to it deselected.
+// This way devices behind multiplexers won't be mistaken with the ones connected directly to the bus.
+func IsolateI2CMuxes(bus i2c.Bus, number int) i2c.Bus {
+	return &i2cMuxIsolated{
+		Bus:    bus,
+		number: number,
+	}
+}
+
+// I2CMuxChannelBus returns number of the I2C bus, under which the `channel`
+// of the multiplexer connected at `addr` on the `bus` is registered.
+func I2CMuxChannelBus(bus int, addr uint16, channel int) int {
+	return I2C_MUX_BUS_OFFSET + bus * (I2C_MUX_MAX_ADDRESS - I2C_MUX_MIN_ADDRESS + 1) * I2C_MUX_CHANNELS +
+		int(addr - I2C_MUX_MIN_ADDRESS) * I2C_MUX_CHANNELS + channel
+}
+
+// I2CLocationOf determines location of I2C device connected at `addr` on the bus with given `number`,
+// which can either be native bus, or the multiplexer channel (see I2CMuxChannelBus).
+func I2CLocationOf(number int, addr uint16) I2CLocation {
+	if number < I2C_MUX_BUS_OFFSET {
+		return I2CLocation{
+			Bus:  number,
+			Addr: addr,
+		}
+	}
+
+	var (
+		n = number - I2C_MUX_BUS_OFFSET
+	)
+
+	return I2CLocation{
+		Bus:     n / ((I2C_MUX_MAX_ADDRESS - I2C_MUX_MIN_ADDRESS + 1) * I2C_MUX_CHANNELS),
+		Mux:     I2C_MUX_MIN_ADDRESS + uint16(n / I2C_MUX_CHANNELS % (I2C_MUX_MAX_ADDRESS - I2C_MUX_MIN_ADDRESS + 1)),
+		Channel: n % I2C_MUX_CHANNELS,
+		Addr:    addr,
+	}
+}
+
+// Muxed determines whether device is connected via multiplexer.
+func (l I2CLocation) Muxed() bool {
+	return l.Mux != 0
+}
+
+// String returns location encoded as bus/address, or bus/mux/channel/address for the multiplexed devices.
+func (l I2CLocation) String() string {
+	if l.Muxed() {
+		return fmt.Sprintf("%d/0x%02X/%d/0x%02X", l.Bus, l.Mux, l.Channel, l.Addr)
+	}
+
+	return fmt.Sprintf("%d/0x%02X", l.Bus, l.Addr)
+}
+
+// Channels returns numbers of the I2C buses, under which multiplexer channels are registered.
+func (m *I2CMux) Channels() []int {
+	var (
+		numbers = make([]int, I2C_MUX_CHANNELS)
+	)
+
+	for ch := range numbers {
+		numbers[ch] = I2CMuxChannelBus(m.Bus, m.Addr, ch)
+	}
+
+	return numbers
+}
+
+func (m *I2CMux) channelName(channel int) string {
+	return fmt.Sprintf("I2C%d.MUX%02X.CH%d", m.Bus, m.Addr, channel)
+}
+
+func (m *I2CMux) open(channel int) (i2c.BusCloser, error) {
+	bus, err := i2creg.Open(shared.NtoI2cBusName(m.Bus)); if err != nil {
+		return nil, err
+	}
+
+	return &i2cMuxChannel{
+		BusCloser: bus,
+		mux:       m,
+		channel:   channel,
+	}, nil
+}
+
+// unregister unregisters first `n` multiplexer channels buses.
+func (m *I2CMux) unregister(n int) {
+	for ch := 0; ch < n; ch++ {
+		_ = i2creg.Unregister(m.channelName(ch))
+	}
+}
+
+// selectMask sets channels `mask` on the multiplexer, unless it is already set.
+func (m *I2CMux) selectMask(bus i2c.Bus, mask int) error {
+	if m.selected == mask {
+		return nil
+	}
+
+	if err := bus.Tx(m.Addr, []byte{byte(mask)}, nil); err != nil {
+		m.selected = -1
+		return errors.Wrapf(err, "failed to select channels of I2C multiplexer at 0x%02X", m.Addr)
+	}
+
+	m.selected = mask
+
+	return nil
+}
+
+// Tx selects the channel and deselects the other multiplexers on the bus, and then does a transaction.
+func (c *i2cMuxChannel) Tx(addr uint16, w, r []byte) error {
+	mb := muxedBus(c.mux.Bus)
+
+	mb.Lock()
+	defer mb.Unlock()
+
+	for _, mux := range mb.muxes {
+		if mux == c.mux {
+			continue
+		}
+
+		if err := mux.selectMask(c.BusCloser, 0); err != nil {
+			return err
+		}
+	}
+
+	if err := c.mux.selectMask(c.BusCloser, 1 << c.channel); err != nil {
+		return err
+	}
+
+	return c.BusCloser.Tx(addr, w, r)
+}
+
+func (c *i2cMuxChannel) SetSpeed(f physic.Frequency) error {
+	return c.BusCloser.SetSpeed(f)
+}
+
+func (c *i2cMuxChannel) String() string {
+	return c.mux.channelName(c.channel)
+}
+
+// Tx deselects channels of all multiplexers on the bus, and then does a transaction.
+func (i *i2cMuxIsolated) Tx(addr uint16, w, r []byte) error {
+	mb := muxedBus(i.number)
+
+	mb.Lock()
+	defer mb.Unlock()
+
+	for _, mux := range mb.muxes {
+		if mux.Addr == addr {
+			continue
+		}
+
+		if err := mux.selectMask(i.Bus, 0); err != nil {
+			return err
+		}
+	}
+
+	return i.Bus.Tx(addr, w, r)
+}
+
+func muxedBus(bus int) *i2cMuxedBus {
+	i2cMuxesMutex.Lock()
+	defer i2cMuxesMutex.Unlock()
+
+	mb, ok := i2cMuxedBuses[bus]; if !ok {
+		mb = &i2cMuxedBus{muxes: make(map[uint16]*I2CMux)}
+		i2cMuxedBuses[bus] = mb
+	}
+
+	return mb
+}
@@ -0,0 +1,75 @@
+package periphery_test
+
+import (
+	"testing"
+
+	"periph.io/x/periph/conn/i2c/i2creg"
+
+	"github.com/timoth-y/chainmetric-iot/drivers/periphery"
+	"github.com/timoth-y/chainmetric-iot/drivers/periphery/emulated"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+func TestI2CDeselectsMuxesOnNativeBus(t *testing.T) {
+	const (
+		number = 3
+		muxAddr = 0x70
+		devAddr = 0x40
+	)
+
+	var (
+		bus = emulated.NewBus("native").
+			Attach(muxAddr, &emulated.Registers{}).
+			Attach(devAddr, emulated.NewHDC1080())
+	)
+
+	unregister, err := bus.Register(number)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unregister()
+
+	mux, err := periphery.RegisterI2CMux(number, muxAddr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer periphery.UnregisterI2CMux(number, muxAddr)
+
+	dev := periphery.NewI2C(devAddr, number)
+	if err := dev.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer dev.Close()
+
+	for _, tc := range []struct {
+		name     string
+		channel  bool
+		deselect int
+	}{
+		{"unknown selection", false, 1},
+		{"already deselected", false, 0},
+		{"after channel transaction", true, 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.channel {
+				channel, err := i2creg.Open(shared.NtoI2cBusName(mux.Channels()[3]))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				_ = channel.Tx(devAddr, []byte{0x00}, make([]byte, 2))
+				_ = channel.Close()
+			}
+
+			before := bus.Transactions(muxAddr)
+
+			if _, err := dev.ReadReg(0xFE); err != nil {
+				t.Fatal(err)
+			}
+
+			if txs := bus.Transactions(muxAddr) - before; txs != tc.deselect {
+				t.Errorf("expected %d multiplexer deselect transactions, got %d", tc.deselect, txs)
+			}
+		})
+	}
+}
@@ -6,5 +6,7 @@ const (
 	MAX17040_SOC_REG = 0x04
 	MAX17040_MOD_REG = 0x06
 	MAX17040_CMD_REG = 0xFE
+
+	MAX17040_VOLTS_PER_LSB = 0.00125
 )
 
@@ -60,16 +60,16 @@ func (ups *UPSController) BatteryLevel() (int, error) {
 	return int(math.Round(raw)), nil
 }
 
-// BatteryVoltage reads current battery voltage.
+// BatteryVoltage reads current battery voltage in volts.
 func (ups *UPSController) BatteryVoltage() (float64, error) {
 	payload, err := ups.ReadRegBytes(MAX17040_VOL_REG, 2)
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to read battery voltage from UPS")
 	}
 
-	raw := (payload[0] << 8 + payload[1]) >> 4
+	raw := (uint16(payload[0]) << 8 | uint16(payload[1])) >> 4
 
-	return float64(raw), nil
+	return float64(raw) * MAX17040_VOLTS_PER_LSB, nil
 }
 
 // IsPlugged determines whether the UPS is plugged in and charging.
@@ -0,0 +1,70 @@
+package power
+
+import (
+	"math"
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/timoth-y/chainmetric-iot/drivers/periphery/emulated"
+)
+
+func TestUPSBatteryOnEmulatedBus(t *testing.T) {
+	var (
+		chip = emulated.NewMAX17040()
+		bus = emulated.NewBus("ups").Attach(MAX17040_ADDRESS, chip)
+		ups = NewUPSController()
+	)
+
+	unregister, err := bus.Register(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unregister()
+
+	// Power pin is GPIO based, so only the fuel gauge part of the UPS is initialized:
+	if err := ups.I2C.Init(); err != nil {
+		t.Fatal(err)
+	}
+	defer ups.I2C.Close()
+
+	for _, tc := range []struct {
+		name    string
+		voltage float64
+		level   float64
+		fault   error
+	}{
+		{"fully charged", 4.2, 100, nil},
+		{"discharging", 3.7, 42.4, nil},
+		{"empty", 3.0, 0, nil},
+		{"bus fault", 3.7, 50, errors.New("bus fault")},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			chip.SetBattery(tc.voltage, tc.level)
+			bus.Fail(MAX17040_ADDRESS, tc.fault)
+			defer bus.Fail(MAX17040_ADDRESS, nil)
+
+			level, err := ups.BatteryLevel()
+			if (err != nil) != (tc.fault != nil) {
+				t.Fatalf("unexpected battery level error: %v", err)
+			}
+
+			voltage, err := ups.BatteryVoltage()
+			if (err != nil) != (tc.fault != nil) {
+				t.Fatalf("unexpected battery voltage error: %v", err)
+			}
+
+			if tc.fault != nil {
+				return
+			}
+
+			if expected := int(math.Round(tc.level)); level != expected {
+				t.Errorf("expected battery level %d%%, got %d%%", expected, level)
+			}
+
+			if math.Abs(voltage - tc.voltage) > MAX17040_VOLTS_PER_LSB {
+				t.Errorf("expected battery voltage %vV, got %vV", tc.voltage, voltage)
+			}
+		})
+	}
+}
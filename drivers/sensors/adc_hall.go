@@ -2,6 +2,7 @@ package sensors
 
 import (
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/timoth-y/chainmetric-core/models"
@@ -26,7 +27,8 @@ func NewADCHall(addr uint16, bus int) sensor.Sensor {
 		ADC: periphery.NewADC(addr, bus, periphery.WithConversion(func(raw float64) float64 {
 			volts := raw / periphery.ADS1115_SAMPLES_PER_READ * periphery.ADS1115_VOLTS_PER_SAMPLE
 			return volts * 1000 / ADC_HALL_SENSITIVITY
-		}), periphery.WithBias(ADC_HALL_BIAS), periphery.WithI2CMutex(adcHallMutex)),
+		}), periphery.WithBias(ADC_HALL_BIAS), periphery.WithI2CMutex(adcHallMutex),
+			periphery.WithSpikeFilter(viper.GetInt("sensors.analog.spike_filter_window"))),
 		samples: viper.GetInt("sensors.analog.samples_per_read"),
 	}
 }
@@ -39,6 +41,10 @@ func (s *ADCHall) Read() float64 {
 	return s.RMS(s.samples, nil)
 }
 
+func (s *ADCHall) ReadDuration() time.Duration {
+	return time.Duration(s.samples) * periphery.ADS1115_CONVERSION_TIME
+}
+
 func (s *ADCHall) Harvest(ctx *sensor.Context) {
 	ctx.WriterFor(metrics.Magnetism).Write(s.Read())
 }
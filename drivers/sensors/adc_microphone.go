@@ -2,6 +2,7 @@ package sensors
 
 import (
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/timoth-y/chainmetric-core/models"
@@ -23,7 +24,8 @@ type ADCMic struct {
 
 func NewADCMicrophone(addr uint16, bus int) sensor.Sensor {
 	return &ADCMic{
-		ADC:     periphery.NewADC(addr, bus, periphery.WithI2CMutex(adcMicMutex)),
+		ADC:     periphery.NewADC(addr, bus, periphery.WithI2CMutex(adcMicMutex),
+			periphery.WithSpikeFilter(viper.GetInt("sensors.analog.spike_filter_window"))),
 		samples: viper.GetInt("sensors.analog.samples_per_read"),
 	}
 }
@@ -37,6 +39,10 @@ func (s *ADCMic) Read() float64 {
 		ADC_MICROPHONE_REGRESSION_C2
 }
 
+func (s *ADCMic) ReadDuration() time.Duration {
+	return time.Duration(s.samples) * periphery.ADS1115_CONVERSION_TIME
+}
+
 func (s *ADCMic) Harvest(ctx *sensor.Context) {
 	ctx.WriterFor(metrics.NoiseLevel).Write(s.Read())
 }
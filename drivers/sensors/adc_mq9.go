@@ -2,6 +2,7 @@ package sensors
 
 import (
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/timoth-y/chainmetric-core/models"
@@ -19,6 +20,7 @@ var (
 type ADCMQ9 struct {
 	periphery.ADC
 	samples int
+	preheat time.Duration
 }
 
 func NewADCMQ9(addr uint16, bus int) sensor.Sensor {
@@ -27,8 +29,10 @@ func NewADCMQ9(addr uint16, bus int) sensor.Sensor {
 			volts := raw / periphery.ADS1115_SAMPLES_PER_READ * periphery.ADS1115_VOLTS_PER_SAMPLE
 			resAir := (ADC_MQ9_RESISTANCE - volts) / volts
 			return resAir / ADC_MQ9_SENSITIVITY * 1000
-		}), periphery.WithBias(ADC_MQ9_BIAS), periphery.WithI2CMutex(adcMQ9Mutex)),
+		}), periphery.WithBias(ADC_MQ9_BIAS), periphery.WithI2CMutex(adcMQ9Mutex),
+			periphery.WithSpikeFilter(viper.GetInt("sensors.analog.spike_filter_window"))),
 		samples: viper.GetInt("sensors.analog.samples_per_read"),
+		preheat: viper.GetDuration("sensors.mq9.preheat"),
 	}
 }
 
@@ -40,6 +44,20 @@ func (s *ADCMQ9) Read() float64 {
 	return s.RMS(s.samples, nil)
 }
 
+func (s *ADCMQ9) ReadDuration() time.Duration {
+	return time.Duration(s.samples) * periphery.ADS1115_CONVERSION_TIME
+}
+
+// WarmUpDuration returns time MQ-9 heater needs to preheat, before sensor readings become valid.
+func (s *ADCMQ9) WarmUpDuration() time.Duration {
+	return s.preheat
+}
+
+// Ready always reports true, since MQ-9 has no means to report heater state.
+func (s *ADCMQ9) Ready() bool {
+	return true
+}
+
 func (s *ADCMQ9) Harvest(ctx *sensor.Context) {
 	ctx.WriterFor(metrics.AirPetroleumConcentration).Write(s.Read())
 }
@@ -2,6 +2,7 @@ package sensors
 
 import (
 	"sync"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/timoth-y/chainmetric-core/models"
@@ -29,7 +30,8 @@ func NewADCPiezo(addr uint16, bus int) sensor.Sensor {
 			volts := raw / periphery.ADS1115_SAMPLES_PER_READ * periphery.ADS1115_VOLTS_PER_SAMPLE
 			shared.Logger.Debug("ADC_Piezo", "-> volts =", volts)
 			return volts
-		}), periphery.WithI2CMutex(adcPiezoMutex)),
+		}), periphery.WithI2CMutex(adcPiezoMutex),
+			periphery.WithSpikeFilter(viper.GetInt("sensors.analog.spike_filter_window"))),
 		samples: viper.GetInt("sensors.analog.samples_per_read"),
 	}
 }
@@ -42,6 +44,10 @@ func (s *ADCPiezo) Read() float64 {
 	return s.RMS(s.samples, nil)
 }
 
+func (s *ADCPiezo) ReadDuration() time.Duration {
+	return time.Duration(s.samples) * periphery.ADS1115_CONVERSION_TIME
+}
+
 func (s *ADCPiezo) Harvest(ctx *sensor.Context) {
 	ctx.WriterFor(metrics.Vibration).Write(s.Read())
 }
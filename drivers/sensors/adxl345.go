@@ -1,20 +1,46 @@
 package sensors
 
 import (
+	"context"
 	"math"
 	"sync"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 	"github.com/timoth-y/chainmetric-core/models"
 
 	"github.com/timoth-y/chainmetric-core/models/metrics"
 
 	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/core/dsp"
 	"github.com/timoth-y/chainmetric-iot/drivers/periphery"
 	"github.com/timoth-y/chainmetric-iot/model"
+	"github.com/timoth-y/chainmetric-iot/shared"
 )
 
 var (
 	adxl345Mutex = &sync.Mutex{}
+
+	// adxl345Rates maps supported output data rates in Hz to their register codes.
+	adxl345Rates = map[int]byte{
+		1600: ADXL345_Rate1600HZ,
+		800:  ADXL345_Rate800HZ,
+		400:  ADXL345_Rate400HZ,
+		200:  ADXL345_Rate200HZ,
+		100:  ADXL345_Rate100HZ,
+		50:   ADXL345_Rate50HZ,
+		25:   ADXL345_Rate25HZ,
+	}
+
+	// adxl345VibrationMetrics defines vibration features metrics per axis in X, Y, Z order.
+	adxl345VibrationMetrics = [3]struct{
+		rms, peak, crest, frequency models.Metric
+	}{
+		{model.VibrationRMSX, model.VibrationPeakX, model.VibrationCrestX, model.VibrationFrequencyX},
+		{model.VibrationRMSY, model.VibrationPeakY, model.VibrationCrestY, model.VibrationFrequencyY},
+		{model.VibrationRMSZ, model.VibrationPeakZ, model.VibrationCrestZ, model.VibrationFrequencyZ},
+	}
 )
 
 const (
@@ -23,13 +49,20 @@ const (
 )
 
 // ADXL345 sensor device.
+//
+// When burst capture is enabled with `sensors.adxl345.burst.rate`, along with acceleration magnitude
+// it captures burst of samples through the chip's FIFO and reports vibration features of each axis.
 type ADXL345 struct {
 	*periphery.I2C
+	burstRate    int
+	burstSamples int
 }
 
 func NewADXL345(addr uint16, bus int) sensor.Sensor {
 	return &ADXL345{
 		I2C: periphery.NewI2C(addr, bus, periphery.WithMutex(adxl345Mutex)),
+		burstRate: viper.GetInt("sensors.adxl345.burst.rate"),
+		burstSamples: viper.GetInt("sensors.adxl345.burst.samples"),
 	}
 }
 
@@ -76,14 +109,128 @@ func (s *ADXL345) ReadAxes() (model.Vector, error) {
 	}, nil
 }
 
+// ReadDuration returns expected duration of the reading, which is prolonged by the burst capture.
+func (s *ADXL345) ReadDuration() time.Duration {
+	if !s.burstEnabled() {
+		return 0
+	}
+
+	return time.Duration(float64(s.burstSamples) / float64(s.burstRate) * 1.5 * float64(time.Second)) +
+		100 * time.Millisecond
+}
+
 func (s *ADXL345) Harvest(ctx *sensor.Context) {
 	ctx.WriterFor(metrics.Acceleration).WriteWithError(toMagnitude(s.ReadAxes()))
+
+	if !s.burstEnabled() || !vibrationRequested(ctx) {
+		return
+	}
+
+	axes, err := s.CaptureBurst(ctx)
+	if err != nil {
+		ctx.Error(errors.Wrap(err, "failed to capture vibration burst"))
+		return
+	}
+
+	for i, samples := range axes {
+		var (
+			features = dsp.Analyze(samples, float64(s.burstRate), 1)
+			m = adxl345VibrationMetrics[i]
+		)
+
+		ctx.WriterFor(m.rms).Write(features.RMS)
+		ctx.WriterFor(m.peak).Write(features.Peak)
+		ctx.WriterFor(m.crest).Write(features.CrestFactor)
+
+		if len(features.Dominant) != 0 {
+			ctx.WriterFor(m.frequency).Write(features.Dominant[0])
+		}
+	}
+}
+
+// CaptureBurst captures burst of acceleration samples with high output data rate through the FIFO
+// and returns them per axis in X, Y, Z order as multiplications of G.
+func (s *ADXL345) CaptureBurst(ctx context.Context) (axes [3][]float64, err error) {
+	rate, ok := adxl345Rates[s.burstRate]
+	if !ok {
+		return axes, errors.Errorf("unsupported burst rate %d Hz", s.burstRate)
+	}
+
+	// Collected samples are drained from FIFO once it is half full, giving enough time for I2C transactions:
+	var (
+		poll = time.Duration(float64(time.Second) * ADXL345_FIFO_SIZE / 2 / float64(s.burstRate))
+		collected = 0
+	)
+
+	if err = s.WriteRegBytes(ADXL345_BW_RATE, rate); err != nil {
+		return
+	}
+
+	defer func() {
+		if err := s.WriteRegBytes(ADXL345_FIFO_CTL, ADXL345_FIFO_BYPASS); err != nil {
+			shared.Logger.Debug(errors.Wrap(err, "failed to reset ADXL345 FIFO mode"))
+		}
+
+		if err := s.WriteRegBytes(ADXL345_BW_RATE, ADXL345_Rate100HZ); err != nil {
+			shared.Logger.Debug(errors.Wrap(err, "failed to reset ADXL345 data rate"))
+		}
+	}()
+
+	// Switching through bypass mode clears samples left in FIFO:
+	if err = s.WriteRegBytes(ADXL345_FIFO_CTL, ADXL345_FIFO_BYPASS); err != nil {
+		return
+	}
+
+	if err = s.WriteRegBytes(ADXL345_FIFO_CTL, ADXL345_FIFO_STREAM); err != nil {
+		return
+	}
+
+	for i := range axes {
+		axes[i] = make([]float64, 0, s.burstSamples)
+	}
+
+	for collected < s.burstSamples {
+		select {
+		case <- ctx.Done():
+			return axes, ctx.Err()
+		case <- time.After(poll):
+		}
+
+		status, err := s.ReadReg(ADXL345_FIFO_STATUS)
+		if err != nil {
+			return axes, err
+		}
+
+		for entries := int(status & ADXL345_FIFO_ENTRIES); entries > 0 && collected < s.burstSamples; entries-- {
+			v, err := s.ReadAxes()
+			if err != nil {
+				return axes, err
+			}
+
+			axes[0] = append(axes[0], v.X)
+			axes[1] = append(axes[1], v.Y)
+			axes[2] = append(axes[2], v.Z)
+			collected++
+		}
+	}
+
+	return axes, nil
 }
 
 func (s *ADXL345) Metrics() []models.Metric {
-	return []models.Metric{
-		metrics.Acceleration,
+	var (
+		available = []models.Metric{
+			metrics.Acceleration,
+		}
+	)
+
+	if s.burstEnabled() {
+		for _, m := range adxl345VibrationMetrics {
+			available = append(available, m.rms, m.peak, m.crest, m.frequency)
+		}
 	}
+
+	return available
 }
 
 func (s *ADXL345) Verify() bool {
@@ -98,6 +245,10 @@ func (s *ADXL345) Verify() bool {
 	return false
 }
 
+func (s *ADXL345) burstEnabled() bool {
+	return s.burstRate > 0 && s.burstSamples > 0
+}
+
 // setRange changes the range of sensor. Available ranges are 2G, 4G, 8G and 16G.
 func (s *ADXL345) setRange(newRange byte) error {
 	format, err := s.ReadReg(ADXL345_DATA_FORMAT); if err != nil {
@@ -117,6 +268,19 @@ func round(f float64, places int) float64 {
 	return math.Floor(f*shift+.5) / shift
 }
 
+// vibrationRequested determines whether any of the vibration features metrics was requested within `ctx`.
+func vibrationRequested(ctx *sensor.Context) bool {
+	for _, m := range adxl345VibrationMetrics {
+		for _, metric := range []models.Metric{m.rms, m.peak, m.crest, m.frequency} {
+			if _, ok := ctx.Pipe[metric]; ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func toMagnitude(vector model.Vector, err error) (float64, error) {
 	r := math.Pow(vector.X, 2) + math.Pow(vector.Y, 2) + math.Pow(vector.Z, 2)
 
@@ -3,6 +3,7 @@ package sensors
 import (
 	"math"
 	"sync"
+	"time"
 
 	"github.com/timoth-y/chainmetric-core/models"
 	"periph.io/x/periph/conn/physic"
@@ -45,6 +46,10 @@ func (s *BMP280) Init() (err error) {
 	return
 }
 
+func (s *BMP280) ReadDuration() time.Duration {
+	return 100 * time.Millisecond
+}
+
 func (s *BMP280) Harvest(ctx *sensor.Context) {
 	s.Lock()
 	defer s.Unlock()
@@ -72,6 +77,17 @@ func (s *BMP280) Metrics() []models.Metric {
 	}
 }
 
+func (s *BMP280) Capabilities() sensor.Capabilities {
+	return sensor.Capabilities{
+		metrics.Temperature: {
+			Unit: "°C", Min: -40, Max: 85, Resolution: 0.01, Accuracy: 1,
+		},
+		metrics.Humidity: {
+			Unit: "%RH", Min: 0, Max: 100, Resolution: 0.01, Accuracy: 3,
+		},
+	}
+}
+
 func (s *BMP280) Verify() bool {
 	if !s.I2C.Verify() {
 		return false
@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/spf13/viper"
 	"github.com/timoth-y/chainmetric-core/models"
 
 	"github.com/timoth-y/chainmetric-core/models/metrics"
@@ -25,11 +26,13 @@ var (
 
 type CCS811 struct {
 	*periphery.I2C
+	warmUp time.Duration
 }
 
 func NewCCS811(addr uint16, bus int) sensor.Sensor {
 	return &CCS811{
 		I2C: periphery.NewI2C(addr, bus, periphery.WithMutex(cc811Mutex)),
+		warmUp: viper.GetDuration("sensors.ccs811.warm_up"),
 	}
 }
 
@@ -92,6 +95,26 @@ func (s *CCS811) Read() (eCO2 float64, eTVOC float64, err error) {
 	return
 }
 
+func (s *CCS811) ReadDuration() time.Duration {
+	// In 1 second drive mode new data should become ready within few data ready checks:
+	return 4 * CCS811_RETRY_TIME * time.Millisecond
+}
+
+// WarmUpDuration returns burn-in time CCS811 needs after each start, before its readings become valid.
+// Brand new device requires 48h initial burn-in, which can be configured with `sensors.ccs811.warm_up`.
+func (s *CCS811) WarmUpDuration() time.Duration {
+	return s.warmUp
+}
+
+// Ready determines whether CCS811 runs application firmware without errors.
+func (s *CCS811) Ready() bool {
+	status, err := s.getStatus(); if err != nil {
+		return false
+	}
+
+	return status & CCS811_FW_MODE_BIT != 0 && status & CCS811_ERROR_BIT == 0
+}
+
 func (s *CCS811) Harvest(ctx *sensor.Context) {
 	eCO2, eTVOC, err := s.Read()
 
@@ -113,6 +136,18 @@ func (s *CCS811) Metrics() []models.Metric {
 	}
 }
 
+func (s *CCS811) Capabilities() sensor.Capabilities {
+	// In 1 second drive mode algorithm results are updated once per second:
+	return sensor.Capabilities{
+		metrics.AirCO2Concentration: {
+			Unit: "ppm", Min: 400, Max: 8192, Resolution: 1, MinInterval: time.Second,
+		},
+		metrics.AirTVOCsConcentration: {
+			Unit: "ppb", Min: 0, Max: 1187, Resolution: 1, MinInterval: time.Second,
+		},
+	}
+}
+
 func (s *CCS811) Verify() bool {
 	if !s.I2C.Verify() {
 		return false
@@ -1,5 +1,7 @@
 package sensors
 
+import "time"
+
 const (
 	ADXL345_ADDRESS        = 0x53
 	BMP280_ADDRESS         = 0x76
@@ -58,6 +60,8 @@ const (
 	ADXL345_BW_RATE            = 0x2C
 	ADXL345_POWER_CTL          = 0x2D
 	ADXL345_MEASURE            = 0x08
+	ADXL345_FIFO_CTL           = 0x38
+	ADXL345_FIFO_STATUS        = 0x39
 
 	// Constants
 	ADXL345_DEVICE_ID = 0xE5
@@ -71,6 +75,14 @@ const (
 	ADXL345_Rate50HZ   = 0x0A
 	ADXL345_Rate25HZ   = 0x09
 
+	// FIFO modes
+	ADXL345_FIFO_BYPASS = 0x00
+	ADXL345_FIFO_STREAM = 0x80
+
+	// FIFO size and number of entries mask of the FIFO status
+	ADXL345_FIFO_SIZE    = 32
+	ADXL345_FIFO_ENTRIES = 0x3F
+
 	// Measurement Range
 	ADXL345_RANGE2G  = 0x00
 	ADXL345_RANGE4G  = 0x01
@@ -155,6 +167,9 @@ const (
 	HDC1080_CONFIGURATION_REGISTER =        0x02
 	HDC1080_DEVICE_ID_REGISTER =            0xFF
 
+	// Time
+	HDC1080_CONVERSION_TIME = 65 * time.Millisecond
+
 	// Device ID
 	HDC1080_DEVICE_ID = 0x10
 
@@ -329,3 +344,9 @@ const (
 	MOCK_DEVICE_ID_REGISTER = 0x0F
 	MOCK_DEVICE_ID          = 0x69
 )
+
+// DS18B20 sensor constants
+const (
+	DS18B20_DEVICES_PATH    = "/sys/bus/w1/devices"
+	DS18B20_CONVERSION_TIME = 750 * time.Millisecond
+)
@@ -0,0 +1,60 @@
+package sensors
+
+import (
+	"periph.io/x/periph/conn/i2c"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/drivers/periphery"
+)
+
+// Drivers of the devices, which share I2C addresses with the ADCs, are probed prior to them,
+// since ADCs chip ID can't tell what is actually connected to it:
+const (
+	adcPriority = iota
+	chipPriority
+)
+
+var (
+	adcProbe = sensor.ChipIDProbe(periphery.ADS1115_DEVICE_ID_REGISTER, periphery.ADS1115_DEVICE_ID)
+)
+
+func init() {
+	sensor.RegisterDriver(sensor.I2CDriver("LSM303C-A", NewAccelerometerLSM303, LSM303C_A_ADDRESS).
+		WithProbe(sensor.ChipIDProbe(LSM303C_A_DEVICE_ID_REGISTER, LSM303C_A_DEVICE_ID)).WithPriority(chipPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("LSM303C-M", NewMagnetometerLSM303, LSM303C_M_ADDRESS).
+		WithProbe(sensor.ChipIDProbe(LSM303C_M_DEVICE_ID_REGISTER, LSM303C_M_DEVICE_ID)).WithPriority(chipPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("HDC1080", NewHDC1080, HDC1080_ADDRESS).
+		WithProbe(sensor.ChipIDProbe(HDC1080_DEVICE_ID_REGISTER, HDC1080_DEVICE_ID)).WithPriority(chipPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("MAX44009", NewMAX44009, MAX44009_ADDRESS, MAX44009_ALT_ADDRESS).
+		WithProbe(sensor.ChipIDProbe(MAX44009_DEVICE_ID_REGISTER, MAX44009_DEVICE_ID)).WithPriority(chipPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("ADXL345", NewADXL345, ADXL345_ADDRESS).
+		WithProbe(sensor.ChipIDProbe(ADXL345_DEVICE_ID_REGISTER, ADXL345_DEVICE_ID)).WithPriority(chipPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("MAX30102", NewMAX30102, MAX30102_ADDRESS).
+		WithProbe(sensor.ChipIDProbe(MAX30102_DEVICE_ID_REGISTER, MAX30102_DEVICE_ID)).WithPriority(chipPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("CCS811", NewCCS811, CCS811_ADDRESS).
+		WithProbe(sensor.ChipIDProbe(CCS811_DEVICE_ID_REGISTER, CCS811_DEVICE_ID)).WithPriority(chipPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("SI1145", NewSI1145, SI1145_ADDRESS).
+		WithProbe(sensor.ChipIDProbe(SI1145_DEVICE_ID_REGISTER, SI1145_DEVICE_ID)).WithPriority(chipPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("BMP280", NewBMXX80, BMP280_ADDRESS).
+		WithProbe(sensor.ChipIDProbe(BMP280_DEVICE_ID_REGISTER, BMP280_DEVICE_ID)).WithPriority(chipPriority))
+
+	sensor.RegisterDriver(sensor.I2CDriver("ADC_Hall", NewADCHall, ADC_HALL_ADDRESS).
+		WithProbe(adcProbe).WithPriority(adcPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("ADC_Microphone", NewADCMicrophone, ADC_MICROPHONE_ADDRESS).
+		WithProbe(adcProbe).WithPriority(adcPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("ADC-MQ9", NewADCMQ9, ADC_MQ9_ADDRESS).
+		WithProbe(adcProbe).WithPriority(adcPriority))
+	sensor.RegisterDriver(sensor.I2CDriver("ADC_Piezo", NewADCPiezo, ADC_PIEZO_ADDRESS).
+		WithProbe(adcProbe).WithPriority(adcPriority))
+	// Flame sensor's ADC can't be told apart from the others, thus it is only available for static configuration:
+	sensor.RegisterDriver(sensor.I2CDriver("ADC_Flame", NewADCFlame, ADC_FLAME_ADDRESS))
+
+	sensor.RegisterDriver(sensor.I2CDriver("MOCK-I2C", NewI2CSensorMock, MOCK_ADDRESS).
+		WithProbe(func(_ i2c.Bus, _ uint16) bool { return true }))
+
+	sensor.RegisterDriver(sensor.Driver{Name: "DS18B20", Build: buildDS18B20})
+	sensor.RegisterDriver(sensor.Driver{Name: "GPIO-Switch", Build: buildGPIOSwitch})
+	sensor.RegisterDriver(sensor.Driver{Name: "MOCK_Static", Build: func(_ sensor.DriverParams) (sensor.Sensor, error) {
+		return NewStaticSensorMock(), nil
+	}})
+}
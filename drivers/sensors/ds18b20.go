@@ -0,0 +1,117 @@
+package sensors
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+)
+
+// DS18B20 implements driver for 1-Wire temperature sensor, which is read through the kernel's w1-therm module.
+type DS18B20 struct {
+	path   string
+	active bool
+}
+
+// NewDS18B20 constructs new DS18B20 driver for the 1-Wire device with given `serial` number,
+// which is exposed by the kernel in `devicesPath` directory.
+func NewDS18B20(serial, devicesPath string) sensor.Sensor {
+	return &DS18B20{
+		path: filepath.Join(devicesPath, serial, "w1_slave"),
+	}
+}
+
+func buildDS18B20(params sensor.DriverParams) (sensor.Sensor, error) {
+	var (
+		serial = stringOption(params, "serial")
+		devicesPath = stringOption(params, "devices_path")
+	)
+
+	if len(serial) == 0 {
+		return nil, errors.New("serial number of the 1-Wire device must be specified with 'serial' option")
+	}
+
+	if len(devicesPath) == 0 {
+		devicesPath = DS18B20_DEVICES_PATH
+	}
+
+	return NewDS18B20(serial, devicesPath), nil
+}
+
+func (s *DS18B20) ID() string {
+	return "DS18B20"
+}
+
+func (s *DS18B20) Init() error {
+	s.active = true
+	return nil
+}
+
+func (s *DS18B20) Read() (float64, error) {
+	data, err := ioutil.ReadFile(s.path); if err != nil {
+		return 0, errors.Wrap(err, "failed to read 1-Wire device")
+	}
+
+	var (
+		lines = strings.Split(strings.TrimSpace(string(data)), "\n")
+	)
+
+	if len(lines) != 2 || !strings.HasSuffix(lines[0], "YES") {
+		return 0, errors.New("1-Wire device reading has failed CRC check")
+	}
+
+	idx := strings.LastIndex(lines[1], "t="); if idx == -1 {
+		return 0, errors.New("1-Wire device reading has no temperature value")
+	}
+
+	milli, err := strconv.Atoi(lines[1][idx + 2:]); if err != nil {
+		return 0, errors.Wrap(err, "failed to parse 1-Wire device temperature value")
+	}
+
+	return float64(milli) / 1000, nil
+}
+
+func (s *DS18B20) ReadDuration() time.Duration {
+	// Kernel module triggers conversion on each read, which takes up to 750ms in 12-bit resolution:
+	return DS18B20_CONVERSION_TIME
+}
+
+func (s *DS18B20) Harvest(ctx *sensor.Context) {
+	ctx.WriterFor(metrics.Temperature).WriteWithError(s.Read())
+}
+
+func (s *DS18B20) Metrics() []models.Metric {
+	return []models.Metric {
+		metrics.Temperature,
+	}
+}
+
+func (s *DS18B20) Capabilities() sensor.Capabilities {
+	return sensor.Capabilities{
+		metrics.Temperature: {
+			Unit: "°C", Min: -55, Max: 125, Resolution: 0.0625, Accuracy: 0.5, MinInterval: DS18B20_CONVERSION_TIME,
+		},
+	}
+}
+
+func (s *DS18B20) Verify() bool {
+	_, err := ioutil.ReadFile(s.path)
+	return err == nil
+}
+
+func (s *DS18B20) Active() bool {
+	return s.active
+}
+
+func (s *DS18B20) Close() error {
+	s.active = false
+	return nil
+}
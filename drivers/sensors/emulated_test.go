@@ -0,0 +1,338 @@
+package sensors
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/drivers/periphery"
+	"github.com/timoth-y/chainmetric-iot/drivers/periphery/emulated"
+)
+
+var (
+	errBusFault = errors.New("bus fault")
+)
+
+// emulatedDriver defines test case of the I2C driver exercised against the chip model attached to emulated bus.
+type emulatedDriver struct {
+	name  string
+	addr  uint16
+	build func(addr uint16, bus int) sensor.Sensor
+	chip  func() emulated.Chip
+	// initialized checks the chip state after the driver initialization.
+	initialized func(chip emulated.Chip) bool
+	expected    map[models.Metric]float64
+	tolerance   float64
+	// silentFaults is set for drivers, which can't tell failed reading apart, since go-ads library swallows
+	// bus errors and returns zero conversion instead. Those are only detected by failed verification.
+	silentFaults bool
+}
+
+func emulatedDrivers() []emulatedDriver {
+	return []emulatedDriver{
+		{
+			name: "HDC1080", addr: HDC1080_ADDRESS, build: NewHDC1080,
+			chip: func() emulated.Chip {
+				chip := emulated.NewHDC1080()
+				chip.SetClimate(21.5, 40)
+				return chip
+			},
+			expected:  map[models.Metric]float64{metrics.Temperature: 21.5, metrics.Humidity: 40},
+			tolerance: 0.01,
+		},
+		{
+			name: "MAX44009", addr: MAX44009_ADDRESS, build: NewMAX44009,
+			chip: func() emulated.Chip {
+				chip := emulated.NewMAX44009()
+				chip.SetLux(1000)
+				return chip
+			},
+			expected:  map[models.Metric]float64{metrics.Luminosity: 1000},
+			tolerance: 5,
+		},
+		{
+			name: "CCS811", addr: CCS811_ADDRESS, build: NewCCS811,
+			chip: func() emulated.Chip {
+				chip := emulated.NewCCS811()
+				chip.SetAirQuality(450, 20)
+				return chip
+			},
+			initialized: func(chip emulated.Chip) bool {
+				return chip.(*emulated.CCS811).Get(CCS811_STATUS) & CCS811_FW_MODE_BIT != 0
+			},
+			expected: map[models.Metric]float64{metrics.AirCO2Concentration: 450, metrics.AirTVOCsConcentration: 20},
+		},
+		{
+			name: "SI1145", addr: SI1145_ADDRESS, build: NewSI1145,
+			chip: func() emulated.Chip {
+				chip := emulated.NewSI1145()
+				chip.SetLight(250, 260, 270, 1200)
+				return chip
+			},
+			initialized: func(chip emulated.Chip) bool {
+				return chip.(*emulated.SI1145).Param(SI1145_PARAM_CHLIST) != 0
+			},
+			expected: map[models.Metric]float64{
+				metrics.UVLight: 250, metrics.VisibleLight: 260, metrics.IRLight: 270, metrics.Proximity: 1200,
+			},
+		},
+		{
+			name: "ADXL345", addr: ADXL345_ADDRESS, build: NewADXL345,
+			chip: func() emulated.Chip {
+				chip := emulated.NewADXL345()
+				chip.SetAcceleration(0.3, 0.4, 0)
+				return chip
+			},
+			initialized: func(chip emulated.Chip) bool {
+				return chip.(*emulated.ADXL345).Measuring()
+			},
+			expected:  map[models.Metric]float64{metrics.Acceleration: 0.5},
+			tolerance: 0.01,
+		},
+		{
+			name: "BMP280", addr: BMP280_ADDRESS, build: NewBMXX80,
+			chip: func() emulated.Chip {
+				return emulated.NewBME280()
+			},
+			expected:  map[models.Metric]float64{metrics.Temperature: 25.08},
+			tolerance: 0.01,
+		},
+		{
+			name: "ADC_Hall", addr: ADC_HALL_ADDRESS, build: NewADCHall,
+			chip: func() emulated.Chip {
+				chip := emulated.NewADS1115()
+				chip.SetConversion(10000)
+				return chip
+			},
+			expected: map[models.Metric]float64{
+				metrics.Magnetism: 10000 / periphery.ADS1115_SAMPLES_PER_READ * periphery.ADS1115_VOLTS_PER_SAMPLE *
+					1000 / ADC_HALL_SENSITIVITY - ADC_HALL_BIAS,
+			},
+			tolerance:    0.01,
+			silentFaults: true,
+		},
+	}
+}
+
+func TestDriversOnEmulatedBus(t *testing.T) {
+	viper.Set("sensors.analog.samples_per_read", 4)
+
+	for _, tc := range emulatedDrivers() {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				chip = tc.chip()
+				bus = registerEmulatedBus(t).Attach(tc.addr, chip)
+				sn = tc.build(tc.addr, 1)
+			)
+
+			if !sn.Verify() {
+				t.Fatal("expected driver to verify the chip")
+			}
+
+			initEmulated(t, sn)
+
+			if tc.initialized != nil && !tc.initialized(chip) {
+				t.Error("expected chip to be set up by the driver initialization")
+			}
+
+			ctx, results := harvestMetrics(sn)
+
+			if ctx.Failed() {
+				t.Fatalf("unexpected harvest error: %v", ctx.LastError())
+			}
+
+			for metric, expected := range tc.expected {
+				if len(results[metric]) == 0 {
+					t.Errorf("expected '%s' to be harvested", metric)
+					continue
+				}
+
+				if value := results[metric][0].Value; math.Abs(value - expected) > tc.tolerance {
+					t.Errorf("expected '%s' to be %v, got %v", metric, expected, value)
+				}
+			}
+
+			if bus.Transactions(tc.addr) == 0 {
+				t.Error("expected driver to communicate with the chip over the bus")
+			}
+		})
+	}
+}
+
+func TestDriversOnEmulatedBusErrors(t *testing.T) {
+	viper.Set("sensors.analog.samples_per_read", 4)
+
+	for _, tc := range emulatedDrivers() {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				bus = registerEmulatedBus(t).Attach(tc.addr, tc.chip())
+				sn = tc.build(tc.addr, 1)
+			)
+
+			initEmulated(t, sn)
+
+			bus.Fail(tc.addr, errBusFault)
+
+			if sn.Verify() {
+				t.Error("expected verification to fail on faulty bus")
+			}
+
+			if ctx, results := harvestMetrics(sn); !tc.silentFaults {
+				if !ctx.Failed() {
+					t.Error("expected harvest error to be reported on faulty bus")
+				}
+
+				for metric := range tc.expected {
+					if len(results[metric]) != 0 {
+						t.Errorf("expected no '%s' reading on faulty bus, got %v", metric, results[metric][0].Value)
+					}
+				}
+			}
+
+			bus.Fail(tc.addr, nil)
+			bus.Detach(tc.addr)
+
+			if sn.Verify() {
+				t.Error("expected verification to fail once the chip is detached")
+			}
+		})
+	}
+}
+
+func TestCCS811ReadinessOnEmulatedBus(t *testing.T) {
+	var (
+		chip = emulated.NewCCS811()
+		sn = NewCCS811(CCS811_ADDRESS, 1).(*CCS811)
+	)
+
+	registerEmulatedBus(t).Attach(CCS811_ADDRESS, chip)
+
+	if sn.Ready() {
+		t.Fatal("expected CCS811 to be not ready before its application is started")
+	}
+
+	initEmulated(t, sn)
+
+	for _, tc := range []struct {
+		name    string
+		errorID byte
+		ready   bool
+	}{
+		{"running", 0, true},
+		{"heater fault", 0x10, false},
+		{"recovered", 0, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			chip.SetError(tc.errorID)
+
+			if sn.Ready() != tc.ready {
+				t.Errorf("expected CCS811 ready to be %v", tc.ready)
+			}
+		})
+	}
+}
+
+func TestLocateI2CSensorOnHotswap(t *testing.T) {
+	var (
+		bus = registerEmulatedBus(t)
+	)
+
+	for _, tc := range []struct {
+		name   string
+		addr   uint16
+		chip   emulated.Chip
+		driver string
+	}{
+		{"light sensor", MAX44009_ADDRESS, emulated.NewMAX44009(), "MAX44009"},
+		{"ADC sharing light sensor address", ADC_MQ9_ADDRESS, emulated.NewADS1115(), "ADC-MQ9"},
+		{"climate sensor", HDC1080_ADDRESS, emulated.NewHDC1080(), "HDC1080"},
+		{"barometer", BMP280_ADDRESS, emulated.NewBME280(), "BMP280"},
+		{"unknown chip", 0x29, &emulated.Registers{}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			bus.Attach(tc.addr, tc.chip)
+
+			factory, ok := LocateI2CSensor(bus, tc.addr)
+			if ok != (len(tc.driver) != 0) {
+				t.Fatalf("expected sensor to be located: %v", !ok)
+			}
+
+			if ok {
+				if id := factory.Build(1).ID(); id != tc.driver {
+					t.Errorf("expected '%s' sensor to be located, got '%s'", tc.driver, id)
+				}
+			}
+
+			bus.Detach(tc.addr)
+
+			if _, ok := LocateI2CSensor(bus, tc.addr); ok {
+				t.Error("expected sensor not to be located once detached")
+			}
+		})
+	}
+}
+
+// registerEmulatedBus registers new emulated bus as I2C bus 1 for the duration of the test.
+func registerEmulatedBus(t *testing.T) *emulated.Bus {
+	var (
+		bus = emulated.NewBus(t.Name())
+	)
+
+	unregister, err := bus.Register(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(unregister)
+
+	return bus
+}
+
+// initEmulated initializes `sn` the way sensor.Registry does and closes it once the test is done.
+func initEmulated(t *testing.T, sn sensor.Sensor) {
+	if sn.Active() {
+		_ = sn.Close()
+	}
+
+	if err := sn.Init(); err != nil {
+		t.Fatalf("unexpected initialization error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		if sn.Active() {
+			_ = sn.Close()
+		}
+	})
+}
+
+// harvestMetrics harvests all metrics of the `sn` sensor.
+func harvestMetrics(sn sensor.Sensor) (*sensor.Context, map[models.Metric][]sensor.ReadingResult) {
+	var (
+		ctx, cancel = context.WithTimeout(context.Background(), 5 * time.Second)
+		readerCtx = sensor.NewReaderContext(ctx, sn)
+		results = make(map[models.Metric][]sensor.ReadingResult)
+	)
+
+	defer cancel()
+
+	for _, metric := range sn.Metrics() {
+		readerCtx.Pipe[metric] = make(chan sensor.ReadingResult, 16)
+	}
+
+	sn.Harvest(readerCtx)
+
+	for metric, ch := range readerCtx.Pipe {
+		for len(ch) > 0 {
+			results[metric] = append(results[metric], <-ch)
+		}
+	}
+
+	return readerCtx, results
+}
@@ -0,0 +1,97 @@
+package sensors
+
+import (
+	"github.com/pkg/errors"
+	"github.com/timoth-y/chainmetric-core/models"
+	"periph.io/x/periph/conn/gpio"
+	"periph.io/x/periph/conn/gpio/gpioreg"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+)
+
+// GPIOSwitch implements driver for the digital sensor connected to GPIO pin (e.g. reed switch, PIR or flame detector),
+// which state is read as 1 when signal is high, or as 0 when it is low, unless the switch is inverted.
+type GPIOSwitch struct {
+	pin      string
+	metric   models.Metric
+	inverted bool
+	io       gpio.PinIO
+}
+
+// NewGPIOSwitch constructs new GPIOSwitch driver for the switch connected to `pin`, which reads `metric`.
+func NewGPIOSwitch(pin string, metric models.Metric, inverted bool) sensor.Sensor {
+	return &GPIOSwitch{
+		pin:      pin,
+		metric:   metric,
+		inverted: inverted,
+	}
+}
+
+func buildGPIOSwitch(params sensor.DriverParams) (sensor.Sensor, error) {
+	var (
+		metric = stringOption(params, "metric")
+	)
+
+	if len(params.Pin) == 0 {
+		return nil, errors.New("GPIO pin must be specified")
+	}
+
+	if len(metric) == 0 {
+		return nil, errors.New("metric read by the switch must be specified with 'metric' option")
+	}
+
+	return NewGPIOSwitch(params.Pin, models.Metric(metric), boolOption(params, "inverted")), nil
+}
+
+func (s *GPIOSwitch) ID() string {
+	return "GPIO-Switch"
+}
+
+func (s *GPIOSwitch) Init() error {
+	var (
+		pin = gpioreg.ByName(s.pin)
+	)
+
+	if pin == nil {
+		return errors.Errorf("GPIO pin '%s' is not available", s.pin)
+	}
+
+	if err := pin.In(gpio.PullNoChange, gpio.NoEdge); err != nil {
+		return errors.Wrapf(err, "failed to setup GPIO pin '%s' as input", s.pin)
+	}
+
+	s.io = pin
+
+	return nil
+}
+
+func (s *GPIOSwitch) Harvest(ctx *sensor.Context) {
+	var (
+		high = s.io.Read() == gpio.High
+	)
+
+	if high != s.inverted {
+		ctx.WriterFor(s.metric).Write(1)
+	} else {
+		ctx.WriterFor(s.metric).Write(0)
+	}
+}
+
+func (s *GPIOSwitch) Metrics() []models.Metric {
+	return []models.Metric {
+		s.metric,
+	}
+}
+
+func (s *GPIOSwitch) Verify() bool {
+	return gpioreg.ByName(s.pin) != nil
+}
+
+func (s *GPIOSwitch) Active() bool {
+	return s.io != nil
+}
+
+func (s *GPIOSwitch) Close() error {
+	s.io = nil
+	return nil
+}
@@ -107,6 +107,12 @@ func (s *HDC1080) ReadHumidity() (float64, error) {
 	return 0, errors.Wrap(err, "failed read from humidity register")
 }
 
+func (s *HDC1080) ReadDuration() time.Duration {
+	// Temperature and humidity are read sequentially, each waits at least for a single conversion,
+	// yet up to a few more retries are expected on a busy bus:
+	return 2 * 3 * HDC1080_CONVERSION_TIME
+}
+
 func (s *HDC1080) Harvest(ctx *sensor.Context) {
 	wg := sync.WaitGroup{}
 	wg.Add(2)
@@ -131,6 +137,17 @@ func (s *HDC1080) Metrics() []models.Metric {
 	}
 }
 
+func (s *HDC1080) Capabilities() sensor.Capabilities {
+	return sensor.Capabilities{
+		metrics.Temperature: {
+			Unit: "°C", Min: -40, Max: 125, Resolution: 0.01, Accuracy: 0.2,
+		},
+		metrics.Humidity: {
+			Unit: "%RH", Min: 0, Max: 100, Resolution: 0.01, Accuracy: 2,
+		},
+	}
+}
+
 func (s *HDC1080) Verify() bool {
 	if !s.I2C.Verify() {
 		return false
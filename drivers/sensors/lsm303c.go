@@ -148,7 +148,16 @@ func (s *LSM303Magnetometer) ID() string {
 }
 
 func (s *LSM303Magnetometer) Harvest(ctx *sensor.Context) {
-	ctx.WriterFor(metrics.Magnetism).WriteWithError(toMagnitude(s.ReadAxes()))
+	axes, err := s.ReadAxes()
+	if err != nil {
+		ctx.Error(err)
+	} else {
+		ctx.WriterFor(metrics.Magnetism).WriteWithError(toMagnitude(axes, nil))
+		ctx.WriterFor(model.MagnetismX).Write(axes.X)
+		ctx.WriterFor(model.MagnetismY).Write(axes.Y)
+		ctx.WriterFor(model.MagnetismZ).Write(axes.Z)
+	}
+
 	ctx.WriterFor(metrics.Temperature).WriteWithError(s.ReadTemperature())
 }
 
@@ -156,6 +165,9 @@ func (s *LSM303Magnetometer) Metrics() []models.Metric {
 	return []models.Metric{
 		metrics.Magnetism,
 		metrics.Temperature,
+		model.MagnetismX,
+		model.MagnetismY,
+		model.MagnetismZ,
 	}
 }
 
@@ -2,6 +2,7 @@ package sensors
 
 import (
 	"sync"
+	"time"
 
 	"github.com/cgxeiji/max3010x"
 	"github.com/timoth-y/chainmetric-core/models"
@@ -51,6 +52,11 @@ func (s *MAX30102) Init() (err error) {
 	return
 }
 
+func (s *MAX30102) ReadDuration() time.Duration {
+	// Heart rate and SpO2 are calculated over the series of samples taken in the course of few seconds:
+	return 5 * time.Second
+}
+
 func (s *MAX30102) Harvest(ctx *sensor.Context) {
 	ctx.WriterFor(metrics.HeartRate).WriteWithError(s.HeartRate())
 	ctx.WriterFor(metrics.BloodOxidation).WriteWithError(s.SpO2())
@@ -3,6 +3,7 @@ package sensors
 import (
 	"math"
 	"sync"
+	"time"
 
 	"github.com/timoth-y/chainmetric-core/models"
 
@@ -50,6 +51,21 @@ func (s *MAX44009) Read() (float64, error) {
 	return lux, nil
 }
 
+func (s *MAX44009) ReadDuration() time.Duration {
+	// In default automatic mode the integration time can take up to 800ms:
+	return 800 * time.Millisecond
+}
+
+// WarmUpDuration returns integration time MAX44009 needs after start before the first valid reading.
+func (s *MAX44009) WarmUpDuration() time.Duration {
+	return s.ReadDuration()
+}
+
+// Ready always reports true, since integration time is the only warm-up MAX44009 requires.
+func (s *MAX44009) Ready() bool {
+	return true
+}
+
 func (s *MAX44009) Harvest(ctx *sensor.Context) {
 	ctx.WriterFor(metrics.Luminosity).WriteWithError(s.Read())
 }
@@ -60,6 +76,14 @@ func (s *MAX44009) Metrics() []models.Metric {
 	}
 }
 
+func (s *MAX44009) Capabilities() sensor.Capabilities {
+	return sensor.Capabilities{
+		metrics.Luminosity: {
+			Unit: "lx", Min: 0, Max: 188000, Resolution: 0.045, MinInterval: s.ReadDuration(),
+		},
+	}
+}
+
 func (s *MAX44009) Verify() bool {
 	if !s.I2C.Verify() {
 		return false
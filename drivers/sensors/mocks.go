@@ -45,6 +45,10 @@ func (s *I2CSensorMock) Init() error {
 	return nil
 }
 
+func (s *I2CSensorMock) ReadDuration() time.Duration {
+	return s.duration
+}
+
 func (s *I2CSensorMock) Harvest(ctx *sensor.Context) {
 	time.Sleep(s.duration)
 
@@ -86,6 +90,10 @@ func (s *StaticSensorMock) Init() error {
 	return nil
 }
 
+func (s *StaticSensorMock) ReadDuration() time.Duration {
+	return s.duration
+}
+
 func (s *StaticSensorMock) Harvest(ctx *sensor.Context) {
 	time.Sleep(s.duration)
 
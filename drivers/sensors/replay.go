@@ -0,0 +1,177 @@
+package sensors
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+)
+
+type (
+	// ReplaySensor implements sensor.Sensor, which plays back readings of the recorded sensor (see sensor.Recorder),
+	// so that field data could be passed through the engine without hardware.
+	//
+	// Each Harvest writes the latest recorded reading of each metric as of the replay clock,
+	// which runs from the start of the recording either at original or accelerated speed.
+	ReplaySensor struct {
+		id       string
+		clock    *ReplayClock
+		readings map[models.Metric][]sensor.RecordedReading
+		metrics  []models.Metric
+		active   bool
+	}
+
+	// ReplayClock maps wall-clock time onto the timeline of the recording,
+	// which is shared by ReplaySensor instances to keep them in sync.
+	ReplayClock struct {
+		mutex   sync.Mutex
+		origin  time.Time
+		end     time.Time
+		started time.Time
+		speed   float64
+	}
+)
+
+// NewReplayClock constructs new ReplayClock instance, which plays recording from `origin` till `end` time
+// with given `speed` multiplier once started.
+func NewReplayClock(origin, end time.Time, speed float64) *ReplayClock {
+	if speed <= 0 {
+		speed = 1
+	}
+
+	return &ReplayClock{
+		origin: origin,
+		end:    end,
+		speed:  speed,
+	}
+}
+
+// Now returns current time in the recording timeline, starting the clock on first call.
+func (c *ReplayClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.started.IsZero() {
+		c.started = time.Now()
+	}
+
+	return c.origin.Add(time.Duration(float64(time.Since(c.started)) * c.speed))
+}
+
+// Finished determines whether the clock has passed the end of the recording.
+func (c *ReplayClock) Finished() bool {
+	return c.Now().After(c.end)
+}
+
+// NewReplaySensor constructs new ReplaySensor instance, which plays back `readings` recorded for sensor with given `id`
+// along with the `clock`. Recorded readings of the other sensors are ignored.
+func NewReplaySensor(id string, clock *ReplayClock, readings []sensor.RecordedReading) sensor.Sensor {
+	var (
+		s = &ReplaySensor{
+			id:       id,
+			clock:    clock,
+			readings: make(map[models.Metric][]sensor.RecordedReading),
+		}
+	)
+
+	for _, reading := range readings {
+		if reading.Source != id {
+			continue
+		}
+
+		if _, ok := s.readings[reading.Metric]; !ok {
+			s.metrics = append(s.metrics, reading.Metric)
+		}
+
+		s.readings[reading.Metric] = append(s.readings[reading.Metric], reading)
+	}
+
+	return s
+}
+
+// ReplaySensors reads recording file by given `path` and constructs ReplaySensor for each of the recorded sensors,
+// playing them along with the shared clock at given `speed`.
+func ReplaySensors(path string, speed float64) ([]sensor.Sensor, error) {
+	readings, err := sensor.ReadRecording(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read recording from '%s'", path)
+	}
+
+	if len(readings) == 0 {
+		return nil, errors.Errorf("recording '%s' is empty", path)
+	}
+
+	var (
+		clock = NewReplayClock(readings[0].Timestamp, readings[len(readings) - 1].Timestamp, speed)
+		seen = make(map[string]bool)
+		sensors []sensor.Sensor
+	)
+
+	for _, reading := range readings {
+		if !seen[reading.Source] {
+			seen[reading.Source] = true
+			sensors = append(sensors, NewReplaySensor(reading.Source, clock, readings))
+		}
+	}
+
+	return sensors, nil
+}
+
+func (s *ReplaySensor) ID() string {
+	return s.id
+}
+
+func (s *ReplaySensor) Init() error {
+	s.active = true
+	return nil
+}
+
+func (s *ReplaySensor) Harvest(ctx *sensor.Context) {
+	var (
+		now = s.clock.Now()
+	)
+
+	if now.After(s.clock.end) {
+		return // Recording is over, there's nothing left to play back.
+	}
+
+	// Recorded values have already been calibrated on the device they were recorded at:
+	ctx.Calibrations = nil
+
+	for metric, readings := range s.readings {
+		// Find the latest reading recorded before the current replay time:
+		i := sort.Search(len(readings), func(i int) bool {
+			return readings[i].Timestamp.After(now)
+		}) - 1
+
+		if i < 0 {
+			continue
+		}
+
+		ctx.WriterFor(metric).
+			WithUncertainty(readings[i].Uncertainty).
+			WithFlags(readings[i].Flags).
+			Write(readings[i].Value)
+	}
+}
+
+func (s *ReplaySensor) Metrics() []models.Metric {
+	return s.metrics
+}
+
+func (s *ReplaySensor) Verify() bool {
+	return true
+}
+
+func (s *ReplaySensor) Active() bool {
+	return s.active
+}
+
+func (s *ReplaySensor) Close() error {
+	s.active = false
+	return nil
+}
@@ -0,0 +1,142 @@
+package sensors
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+)
+
+const replayStep = 400 * time.Millisecond
+
+func TestRecordingReplayRoundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		file  string
+		speed float64
+	}{
+		{"plain at original speed", "readings.csv", 1},
+		{"compressed at original speed", "readings.csv.gz", 1},
+		{"plain accelerated", "readings.csv", 4},
+		{"compressed accelerated", "readings.csv.gz", 8},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				path = filepath.Join(t.TempDir(), tc.file)
+				origin = time.Now().Add(-time.Hour)
+			)
+
+			record(t, path, origin)
+
+			replayed, err := ReplaySensors(path, tc.speed)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(replayed) != 2 {
+				t.Fatalf("expected 2 recorded sensors, got %d", len(replayed))
+			}
+
+			var (
+				hdc = replayed[0]
+				clock = hdc.(*ReplaySensor).clock
+				wall = func(recorded time.Duration) time.Duration {
+					return time.Duration(float64(recorded) / tc.speed)
+				}
+			)
+
+			if hdc.ID() != "HDC1080@1/0x40" {
+				t.Fatalf("expected sensors in order of recording, got '%s' first", hdc.ID())
+			}
+
+			start := time.Now()
+			clock.Now() // starts the clock
+
+			for i, expected := range []float64{21, 22, 23} {
+				// Sample in the middle of recorded steps, so that the clock jitter won't cross their boundaries:
+				time.Sleep(time.Until(start.Add(wall(time.Duration(i) * replayStep + replayStep / 2))))
+
+				if elapsed, played := time.Since(start), clock.Now().Sub(origin);
+					played < time.Duration(float64(elapsed) * tc.speed) - replayStep / 4 ||
+						played > time.Duration(float64(elapsed) * tc.speed) + replayStep / 4 {
+					t.Errorf("expected replay clock at %v, got %v", time.Duration(float64(elapsed) * tc.speed), played)
+				}
+
+				results := harvest(hdc, metrics.Temperature)
+
+				if i == 2 {
+					if len(results) != 0 {
+						t.Errorf("expected nothing to be replayed after recording end, got %+v", results)
+					}
+
+					break
+				}
+
+				if len(results) != 1 || results[0].Value != expected {
+					t.Fatalf("step %d: expected replayed value %v, got %+v", i, expected, results)
+				}
+
+				if results[0].Uncertainty != 0.2 || !results[0].Flags.Has(sensor.Provisional) {
+					t.Errorf("step %d: expected recorded uncertainty and flags to be replayed, got %+v", i, results[0])
+				}
+			}
+		})
+	}
+}
+
+// record writes temperature readings of two steps and the trailing humidity reading,
+// which marks the end of the recording, to the recording file by given `path`.
+func record(t *testing.T, path string, origin time.Time) {
+	recorder, err := sensor.NewRecorder(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, readings := range []map[models.Metric][]sensor.ReadingResult{
+		{
+			metrics.Temperature: {{
+				Source: "HDC1080@1/0x40", Value: 21, Timestamp: origin, Uncertainty: 0.2, Flags: sensor.Provisional,
+			}},
+		},
+		{
+			metrics.Temperature: {{
+				Source: "HDC1080@1/0x40", Value: 22, Timestamp: origin.Add(replayStep), Uncertainty: 0.2, Flags: sensor.Provisional,
+			}},
+			metrics.Humidity: {{
+				Source: "BMP280@1/0x76", Value: 40, Timestamp: origin.Add(2 * replayStep),
+			}},
+		},
+	} {
+		if err := recorder.Record(readings); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := recorder.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func harvest(sn sensor.Sensor, metric models.Metric) []sensor.ReadingResult {
+	var (
+		ctx = sensor.NewReaderContext(context.Background(), sn)
+		results []sensor.ReadingResult
+	)
+
+	ctx.Pipe[metric] = make(chan sensor.ReadingResult, 16)
+	sn.Harvest(ctx)
+
+	for {
+		select {
+		case result := <-ctx.Pipe[metric]:
+			results = append(results, result)
+		default:
+			return results
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package sensors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/drivers/periphery"
+	"github.com/timoth-y/chainmetric-iot/model/config"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+// StaticSensors validates static sensors `configs` and builds sensors with the drivers they refer to.
+// Sensor is identified by the instance ID formed from its location, when it is known, or by its configured ID,
+// which is then required to tell apart sensors built with the same driver.
+func StaticSensors(configs []config.StaticSensorConfig) ([]sensor.Sensor, error) {
+	var (
+		sensors = make([]sensor.Sensor, 0, len(configs))
+		ids = make(map[string]int, len(configs))
+	)
+
+	for i, cfg := range configs {
+		driver, ok := sensor.LookupDriver(cfg.Driver)
+		if !ok {
+			return nil, errors.Errorf("static sensor #%d: unknown driver '%s', available ones are: %s",
+				i, cfg.Driver, strings.Join(driverNames(), ", "))
+		}
+
+		sn, err := driver.Build(sensor.DriverParams{
+			Bus:     cfg.Bus,
+			Address: cfg.Address,
+			Pin:     cfg.Pin,
+			Options: cfg.Options,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "static sensor #%d: failed to build '%s' sensor", i, driver.Name)
+		}
+
+		// Sensor, which location is known, is identified by instance ID and the configured ID becomes its alias,
+		// otherwise the configured ID is the only way to tell it apart from the others sharing the driver:
+		if location := staticLocation(driver, cfg); len(location) != 0 {
+			sn = sensor.WithLocation(sn, location)
+
+			if err := sensor.RegisterAlias(sn.ID(), cfg.ID); err != nil {
+				return nil, errors.Wrapf(err, "static sensor #%d", i)
+			}
+		} else {
+			sn = sensor.WithID(sn, cfg.ID)
+		}
+
+		if j, ok := ids[sn.ID()]; ok {
+			return nil, errors.Errorf("static sensor #%d: ID '%s' is already taken by sensor #%d, unique 'id' is required",
+				i, sn.ID(), j)
+		}
+
+		if !sn.Verify() {
+			shared.Logger.Warningf("Static sensor %s can't be verified, it might be disconnected",
+				sensor.DisplayName(sn.ID()))
+		}
+
+		// Verify may leave sensor partially initialized, so it is closed to be initialized from scratch on first use:
+		if sn.Active() {
+			shared.Execute(sn.Close, fmt.Sprintf("failed to close connection to '%s' sensor", sensor.DisplayName(sn.ID())))
+		}
+
+		ids[sn.ID()] = i
+		sensors = append(sensors, sn)
+	}
+
+	return sensors, nil
+}
+
+// staticLocation determines location of the static sensor defined by `cfg`,
+// which is known for the I2C-based and GPIO-based sensors.
+func staticLocation(driver sensor.Driver, cfg config.StaticSensorConfig) string {
+	switch {
+	case driver.NewI2C != nil:
+		var (
+			addr = cfg.Address
+		)
+
+		if addr == 0 && len(driver.Addresses) != 0 {
+			addr = driver.Addresses[0]
+		}
+
+		return periphery.I2CLocationOf(cfg.Bus, addr).String()
+	case len(cfg.Pin) != 0:
+		return cfg.Pin
+	default:
+		return ""
+	}
+}
+
+func driverNames() []string {
+	var (
+		names []string
+	)
+
+	for _, driver := range sensor.Drivers() {
+		names = append(names, driver.Name)
+	}
+
+	return names
+}
+
+func stringOption(params sensor.DriverParams, key string) string {
+	if v, ok := params.Options[key]; ok && v != nil {
+		return fmt.Sprint(v)
+	}
+
+	return ""
+}
+
+func boolOption(params sensor.DriverParams, key string) bool {
+	switch v := params.Options[key].(type) {
+	case bool:
+		return v
+	case string:
+		b, _ := strconv.ParseBool(v)
+		return b
+	default:
+		return false
+	}
+}
@@ -0,0 +1,56 @@
+package sensors
+
+import (
+	"testing"
+
+	"github.com/op/go-logging"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/drivers/periphery/emulated"
+	"github.com/timoth-y/chainmetric-iot/model/config"
+	"github.com/timoth-y/chainmetric-iot/shared"
+)
+
+func init() {
+	shared.Logger = logging.MustGetLogger("test")
+}
+
+func TestStaticSensorIsInitializedOnAcquire(t *testing.T) {
+	var (
+		bus = emulated.NewBus("static").Attach(CCS811_ADDRESS, emulated.NewCCS811())
+		registry = sensor.NewRegistry(0)
+	)
+
+	unregister, err := bus.Register(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unregister()
+
+	static, err := StaticSensors([]config.StaticSensorConfig{{Driver: "CCS811", Bus: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sn := static[0]
+
+	if sn.Active() {
+		t.Fatal("expected static sensor to be left inactive after verification")
+	}
+
+	registry.Add(sn)
+
+	release, initialized, err := registry.Acquire(sn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+
+	if !initialized {
+		t.Fatal("expected static sensor to be initialized on first acquire")
+	}
+
+	if !sn.(sensor.WarmingUp).Ready() {
+		t.Error("expected CCS811 application to be started on initialization")
+	}
+}
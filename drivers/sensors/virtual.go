@@ -0,0 +1,178 @@
+package sensors
+
+import (
+	"math"
+
+	"github.com/spf13/viper"
+	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/metrics"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+	"github.com/timoth-y/chainmetric-iot/model"
+)
+
+// nanoPascalsPerHectoPascal is used to convert pressure readings,
+// which are written by drivers as physic.Pressure in nano pascals.
+const nanoPascalsPerHectoPascal = 1e11
+
+type (
+	// VirtualSensor implements sensor.Virtual, which derives single models.Metric from the readings of other sensors.
+	VirtualSensor struct {
+		id      string
+		inputs  []models.Metric
+		output  models.Metric
+		formula Formula
+	}
+
+	// Formula defines function for deriving value from the `in` readings values of input metrics.
+	Formula func(in map[models.Metric]float64) float64
+)
+
+// NewVirtualSensor constructs new VirtualSensor instance, which derives `output` metric by `formula`
+// from the readings of the `inputs` metrics.
+func NewVirtualSensor(id string, output models.Metric, formula Formula, inputs ...models.Metric) sensor.Sensor {
+	return &VirtualSensor{
+		id:      id,
+		inputs:  inputs,
+		output:  output,
+		formula: formula,
+	}
+}
+
+// VirtualSensors returns all available virtual sensors.
+func VirtualSensors() []sensor.Sensor {
+	return []sensor.Sensor{
+		NewVirtualSensor("VIRTUAL_DewPoint", model.DewPoint, DewPoint,
+			metrics.Temperature, metrics.Humidity),
+		NewVirtualSensor("VIRTUAL_AbsoluteHumidity", model.AbsoluteHumidity, AbsoluteHumidity,
+			metrics.Temperature, metrics.Humidity),
+		NewVirtualSensor("VIRTUAL_HeatIndex", model.HeatIndex, HeatIndex,
+			metrics.Temperature, metrics.Humidity),
+		NewVirtualSensor("VIRTUAL_MagneticHeading", model.MagneticHeading, MagneticHeading,
+			model.MagnetismX, model.MagnetismY),
+		NewVirtualSensor("VIRTUAL_SeaLevelAltitude", model.SeaLevelAltitude,
+			SeaLevelAltitude(viper.GetFloat64("sensors.virtual.sea_level_pressure")),
+			metrics.Pressure, metrics.Temperature),
+	}
+}
+
+func (s *VirtualSensor) ID() string {
+	return s.id
+}
+
+func (s *VirtualSensor) Init() error {
+	return nil
+}
+
+func (s *VirtualSensor) Harvest(ctx *sensor.Context) {
+	var (
+		in = make(map[models.Metric]float64, len(s.inputs))
+	)
+
+	for _, metric := range s.inputs {
+		reading, ok := ctx.Input(metric)
+		if !ok {
+			return // Missing inputs is not the virtual sensor failure, there's just nothing to derive from.
+		}
+
+		in[metric] = reading.Value
+	}
+
+	ctx.WriterFor(s.output).WithFlags(sensor.Estimated).Write(s.formula(in))
+}
+
+func (s *VirtualSensor) Metrics() []models.Metric {
+	return []models.Metric{
+		s.output,
+	}
+}
+
+func (s *VirtualSensor) Inputs() []models.Metric {
+	return s.inputs
+}
+
+func (s *VirtualSensor) Verify() bool {
+	return true
+}
+
+func (s *VirtualSensor) Active() bool {
+	return true
+}
+
+func (s *VirtualSensor) Close() error {
+	return nil
+}
+
+// DewPoint calculates dew point from temperature and relative humidity with Magnus formula.
+func DewPoint(in map[models.Metric]float64) float64 {
+	var (
+		t = in[metrics.Temperature]
+		rh = in[metrics.Humidity]
+		b, c = 17.62, 243.12
+		gamma = math.Log(rh / 100) + b * t / (c + t)
+	)
+
+	return c * gamma / (b - gamma)
+}
+
+// AbsoluteHumidity calculates mass of water vapour in air from temperature and relative humidity.
+func AbsoluteHumidity(in map[models.Metric]float64) float64 {
+	var (
+		t = in[metrics.Temperature]
+		rh = in[metrics.Humidity]
+	)
+
+	return 6.112 * math.Exp(17.67 * t / (t + 243.5)) * rh * 2.1674 / (273.15 + t)
+}
+
+// HeatIndex calculates apparent temperature from temperature and relative humidity
+// with Rothfusz regression used by the US National Weather Service.
+func HeatIndex(in map[models.Metric]float64) float64 {
+	var (
+		t = in[metrics.Temperature] * 9 / 5 + 32
+		rh = in[metrics.Humidity]
+		hi = 0.5 * (t + 61 + (t - 68) * 1.2 + rh * 0.094)
+	)
+
+	// Simple formula is accurate enough for the mild conditions:
+	if (hi + t) / 2 >= 80 {
+		hi = -42.379 + 2.04901523 * t + 10.14333127 * rh -
+			0.22475541 * t * rh - 0.00683783 * t * t - 0.05481717 * rh * rh +
+			0.00122874 * t * t * rh + 0.00085282 * t * rh * rh - 0.00000199 * t * t * rh * rh
+
+		switch {
+		case rh < 13 && t >= 80 && t <= 112:
+			hi -= (13 - rh) / 4 * math.Sqrt((17 - math.Abs(t - 95)) / 17)
+		case rh > 85 && t >= 80 && t <= 87:
+			hi += (rh - 85) / 10 * (87 - t) / 5
+		}
+	}
+
+	return (hi - 32) * 5 / 9
+}
+
+// MagneticHeading calculates heading from magnetometer X and Y axes, assuming it is placed horizontally.
+func MagneticHeading(in map[models.Metric]float64) float64 {
+	var (
+		heading = math.Atan2(in[model.MagnetismY], in[model.MagnetismX]) * 180 / math.Pi
+	)
+
+	if heading < 0 {
+		heading += 360
+	}
+
+	return heading
+}
+
+// SeaLevelAltitude returns Formula, which calculates altitude from pressure and temperature
+// with hypsometric equation relatively to the given `seaLevelPressure` in hPa.
+func SeaLevelAltitude(seaLevelPressure float64) Formula {
+	return func(in map[models.Metric]float64) float64 {
+		var (
+			p = in[metrics.Pressure] / nanoPascalsPerHectoPascal
+			t = in[metrics.Temperature]
+		)
+
+		return (math.Pow(seaLevelPressure / p, 1 / 5.257) - 1) * (t + 273.15) / 0.0065
+	}
+}
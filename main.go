@@ -8,6 +8,7 @@ import (
 	"github.com/timoth-y/chainmetric-iot/controllers/device/modules"
 	"github.com/timoth-y/chainmetric-iot/controllers/gui"
 	core "github.com/timoth-y/chainmetric-iot/core/dev"
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
 	dsp "github.com/timoth-y/chainmetric-iot/drivers/display"
 	"github.com/timoth-y/chainmetric-iot/network/localnet"
 
@@ -65,6 +66,47 @@ func startup() {
 		shared.MustExecute(display.Init, "failed initializing display")
 	}
 
+	device.RegisterStaticSensors(sensors.VirtualSensors()...)
+
+	shared.MustExecute(func() error {
+		for id, alias := range viper.GetStringMapString("sensors.aliases") {
+			if err := sensor.RegisterAlias(id, alias); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, "failed assigning sensors aliases")
+
+	shared.MustExecute(func() error {
+		var configs []config.StaticSensorConfig
+
+		// Static sensors options are arbitrary maps, thus can't be read via shared.MustUnmarshalFromConfig:
+		if err := viper.UnmarshalKey("sensors.static", &configs); err != nil {
+			return err
+		}
+
+		statics, err := sensors.StaticSensors(configs)
+		if err != nil {
+			return err
+		}
+
+		device.RegisterStaticSensors(statics...)
+		return nil
+	}, "failed configuring static sensors")
+
+	if path := viper.GetString("sensors.replay.file"); len(path) != 0 {
+		shared.MustExecute(func() error {
+			replays, err := sensors.ReplaySensors(path, viper.GetFloat64("sensors.replay.speed"))
+			if err != nil {
+				return err
+			}
+
+			device.RegisterStaticSensors(replays...)
+			return nil
+		}, "failed replaying sensors readings recording")
+	}
+
 	if viper.GetBool("mocks.debug_env") {
 		device.RegisterStaticSensors(sensors.NewStaticSensorMock())
 	}
@@ -0,0 +1,16 @@
+package config
+
+// AggregationConfig defines configuration of the sensor readings aggregation,
+// which is used when multiply sensors supply the same metric.
+type AggregationConfig struct {
+	Default string                             `yaml:"default" mapstructure:"default"`
+	Metrics map[string]MetricAggregationConfig `yaml:"metrics" mapstructure:"metrics"`
+}
+
+// MetricAggregationConfig defines aggregation strategy configuration for a specific metric.
+type MetricAggregationConfig struct {
+	Strategy string             `yaml:"strategy" mapstructure:"strategy"`
+	Trim     float64            `yaml:"trim" mapstructure:"trim"`
+	Priority []string           `yaml:"priority" mapstructure:"priority"`
+	Accuracy map[string]float64 `yaml:"accuracy" mapstructure:"accuracy"`
+}
@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+// DeadbandConfig defines configuration of the report-by-exception mode for periodic readings.
+type DeadbandConfig struct {
+	Absolute   float64                         `yaml:"absolute" mapstructure:"absolute"`
+	Relative   float64                         `yaml:"relative" mapstructure:"relative"`
+	MaxSilence time.Duration                   `yaml:"max_silence" mapstructure:"max_silence"`
+	Metrics    map[string]MetricDeadbandConfig `yaml:"metrics" mapstructure:"metrics"`
+}
+
+// MetricDeadbandConfig defines deadband thresholds for a specific metric.
+// Omitted thresholds leave the default ones in place.
+type MetricDeadbandConfig struct {
+	Absolute *float64 `yaml:"absolute" mapstructure:"absolute"`
+	Relative *float64 `yaml:"relative" mapstructure:"relative"`
+}
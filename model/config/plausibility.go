@@ -0,0 +1,14 @@
+package config
+
+// PlausibilityConfig defines configuration of the physical plausibility limits for sensor readings.
+type PlausibilityConfig struct {
+	Metrics map[string]MetricPlausibilityConfig `yaml:"metrics" mapstructure:"metrics"`
+}
+
+// MetricPlausibilityConfig defines plausibility limits for a specific metric.
+// Omitted bounds leave the built-in ones in place.
+type MetricPlausibilityConfig struct {
+	Min     *float64 `yaml:"min" mapstructure:"min"`
+	Max     *float64 `yaml:"max" mapstructure:"max"`
+	MaxRate *float64 `yaml:"max_rate" mapstructure:"max_rate"`
+}
@@ -0,0 +1,12 @@
+package config
+
+// StaticSensorConfig defines configuration of the sensor, which can't be detected automatically,
+// thus is statically registered on the device with the driver referred by its name.
+type StaticSensorConfig struct {
+	Driver  string                 `yaml:"driver" mapstructure:"driver"`
+	ID      string                 `yaml:"id" mapstructure:"id"`
+	Bus     int                    `yaml:"bus" mapstructure:"bus"`
+	Address uint16                 `yaml:"address" mapstructure:"address"`
+	Pin     string                 `yaml:"pin" mapstructure:"pin"`
+	Options map[string]interface{} `yaml:"options" mapstructure:"options"`
+}
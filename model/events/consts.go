@@ -27,4 +27,22 @@ const (
 
 	// RequestHandled identifies event on handling metric reading request
 	RequestHandled = "request.handled"
+
+	// SensorDegraded identifies event for sensor.Sensor being quarantined due to consecutive failures.
+	SensorDegraded = "sensor.degraded"
+
+	// SensorRecovered identifies event for quarantined sensor.Sensor being successfully re-probed.
+	SensorRecovered = "sensor.recovered"
+
+	// ExcursionDetected identifies event for readings going beyond the limits of models.Requirements.
+	ExcursionDetected = "requirements.excursion.detected"
+
+	// ExcursionResolved identifies event for readings returning within the limits of models.Requirements.
+	ExcursionResolved = "requirements.excursion.resolved"
+
+	// DeviceSensingPaused identifies event for the models.Device sensing being paused by remote command.
+	DeviceSensingPaused = "device.sensing.paused"
+
+	// DeviceSensingResumed identifies event for the models.Device sensing being resumed by remote command.
+	DeviceSensingResumed = "device.sensing.resumed"
 )
@@ -1,6 +1,8 @@
 package events
 
 import (
+	"time"
+
 	"github.com/timoth-y/chainmetric-core/models"
 	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
 	"github.com/timoth-y/chainmetric-iot/model"
@@ -14,7 +16,7 @@ type DeviceLocationChangedPayload struct {
 
 // MetricReadingsPostFailedPayload defines payload for MetricReadingsPostFailed event.
 type MetricReadingsPostFailedPayload struct {
-	models.MetricReadings
+	model.MetricReadingsRecord
 	Error error
 }
 
@@ -30,7 +32,30 @@ type RequirementsChangedPayload struct {
 }
 
 // SensorsRegisterChangedPayload defines payload for SensorsRegisterChanged event.
+// Sensors are referred by their instance IDs (see sensor.InstanceID).
 type SensorsRegisterChangedPayload struct {
 	Added   []sensor.Sensor
 	Removed []string
 }
+
+// SensorDegradedPayload defines payload for SensorDegraded event.
+type SensorDegradedPayload struct {
+	SensorID string
+	Failures int
+	Error    error
+	RetryIn  time.Duration
+}
+
+// SensorRecoveredPayload defines payload for SensorRecovered event.
+type SensorRecoveredPayload struct {
+	SensorID string
+	Downtime time.Duration
+}
+
+// ExcursionPayload defines payload for ExcursionDetected and ExcursionResolved events.
+type ExcursionPayload struct {
+	RequirementsID string
+	AssetID        string
+	Metrics        []models.Metric
+	Values         map[models.Metric]float64
+}
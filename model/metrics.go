@@ -0,0 +1,57 @@
+package model
+
+import (
+	"github.com/timoth-y/chainmetric-core/models"
+)
+
+// Additional models.Metric supported by device on top of the ones defined in chainmetric-core.
+const (
+	// MagnetismX is a magnetic field strength along the X axis of the magnetometer.
+	MagnetismX models.Metric = "mgx"
+	// MagnetismY is a magnetic field strength along the Y axis of the magnetometer.
+	MagnetismY models.Metric = "mgy"
+	// MagnetismZ is a magnetic field strength along the Z axis of the magnetometer.
+	MagnetismZ models.Metric = "mgz"
+
+	// DewPoint is a temperature in °C, at which air becomes saturated with water vapour.
+	DewPoint models.Metric = "dpt"
+	// AbsoluteHumidity is a mass of water vapour in g/m³ of air.
+	AbsoluteHumidity models.Metric = "ahd"
+	// HeatIndex is an apparent temperature in °C, perceived when humidity is combined with air temperature.
+	HeatIndex models.Metric = "hix"
+	// MagneticHeading is an angle in degrees between magnetometer's X axis and magnetic north.
+	MagneticHeading models.Metric = "hdg"
+	// SeaLevelAltitude is an altitude in meters above sea level, corrected with air temperature.
+	SeaLevelAltitude models.Metric = "sla"
+)
+
+// Vibration features of the accelerometer burst capture per axis.
+const (
+	// VibrationRMSX is a root mean square of acceleration in g along the X axis with gravity removed.
+	VibrationRMSX models.Metric = "vrx"
+	// VibrationRMSY is a root mean square of acceleration in g along the Y axis with gravity removed.
+	VibrationRMSY models.Metric = "vry"
+	// VibrationRMSZ is a root mean square of acceleration in g along the Z axis with gravity removed.
+	VibrationRMSZ models.Metric = "vrz"
+
+	// VibrationPeakX is a peak acceleration in g along the X axis with gravity removed.
+	VibrationPeakX models.Metric = "vpx"
+	// VibrationPeakY is a peak acceleration in g along the Y axis with gravity removed.
+	VibrationPeakY models.Metric = "vpy"
+	// VibrationPeakZ is a peak acceleration in g along the Z axis with gravity removed.
+	VibrationPeakZ models.Metric = "vpz"
+
+	// VibrationCrestX is a crest factor of acceleration along the X axis, revealing shocks.
+	VibrationCrestX models.Metric = "vcx"
+	// VibrationCrestY is a crest factor of acceleration along the Y axis, revealing shocks.
+	VibrationCrestY models.Metric = "vcy"
+	// VibrationCrestZ is a crest factor of acceleration along the Z axis, revealing shocks.
+	VibrationCrestZ models.Metric = "vcz"
+
+	// VibrationFrequencyX is a dominant vibration frequency in Hz along the X axis.
+	VibrationFrequencyX models.Metric = "vfx"
+	// VibrationFrequencyY is a dominant vibration frequency in Hz along the Y axis.
+	VibrationFrequencyY models.Metric = "vfy"
+	// VibrationFrequencyZ is a dominant vibration frequency in Hz along the Z axis.
+	VibrationFrequencyZ models.Metric = "vfz"
+)
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
+)
+
+// MetricReadingsRecord defines models.MetricReadings record extended with metadata of each reading value.
+type MetricReadingsRecord struct {
+	models.MetricReadings
+	Meta map[models.Metric]ReadingMeta `json:"meta,omitempty"`
+	// Excursions lists metrics, which readings are beyond the limits of the requirements being handled.
+	Excursions []models.Metric `json:"excursions,omitempty"`
+	// Suppressed is a number of unchanged readings, which weren't posted since the previous record.
+	Suppressed int `json:"suppressed,omitempty"`
+	// Summary contains statistics of each metric readings sampled over the period, when windowed aggregation is used.
+	Summary map[models.Metric]WindowSummary `json:"summary,omitempty"`
+}
+
+// WindowSummary defines statistics of the metric readings sampled over the aggregation window.
+type WindowSummary struct {
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	Mean   float64 `json:"mean"`
+	StdDev float64 `json:"stddev"`
+	Count  int     `json:"count"`
+	// TimeAboveLimit is a number of seconds readings stayed above the max limit of the requirements.
+	TimeAboveLimit float64 `json:"time_above_limit,omitempty"`
+	// TimeBelowLimit is a number of seconds readings stayed below the min limit of the requirements.
+	TimeBelowLimit float64 `json:"time_below_limit,omitempty"`
+}
+
+// ReadingMeta defines metadata describing origin and quality of the reading value.
+type ReadingMeta struct {
+	Source      string              `json:"source"`
+	// Alias is a human readable name assigned to the source sensor, if any.
+	Alias       string              `json:"alias,omitempty"`
+	Timestamp   time.Time           `json:"timestamp"`
+	Uncertainty float64             `json:"uncertainty,omitempty"`
+	Flags       sensor.QualityFlags `json:"flags,omitempty"`
+	Calibration int                 `json:"calibration_version,omitempty"`
+}
+
+// NewReadingMeta constructs ReadingMeta from the given sensor.ReadingResult.
+func NewReadingMeta(result sensor.ReadingResult) ReadingMeta {
+	return ReadingMeta{
+		Source:      result.Source,
+		Alias:       sensor.Alias(result.Source),
+		Timestamp:   result.Timestamp,
+		Uncertainty: result.Uncertainty,
+		Flags:       result.Flags,
+		Calibration: result.CalibrationVersion,
+	}
+}
+
+// Encode serializes the MetricReadingsRecord model.
+func (r MetricReadingsRecord) Encode() []byte {
+	data, err := json.Marshal(r); if err != nil {
+		return nil
+	}
+
+	return data
+}
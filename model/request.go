@@ -2,6 +2,7 @@ package model
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/timoth-y/chainmetric-core/models"
@@ -13,23 +14,36 @@ type SensorsReadingRequest struct {
 	AssetID string
 	Period  time.Duration
 	Metrics models.Metrics
+	Limits  models.RequirementsMap
+	mutex   sync.Mutex
 	cancel  context.CancelFunc
 }
 
 // Cancel calls assigned cancel func to cancel request receiver routine.
+// Request can be processed once again after being canceled.
 func (sr *SensorsReadingRequest) Cancel() {
-	if sr.IsProcessed() {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
+	if sr.cancel != nil {
 		sr.cancel()
+		sr.cancel = nil
 	}
 }
 
 // SetCancel sets `cancel` func for canceling request receiver routine.
 func (sr *SensorsReadingRequest) SetCancel(cancel context.CancelFunc) {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
 	sr.cancel = cancel
 }
 
 // IsProcessed determines whether the request is being already processed.
 func (sr *SensorsReadingRequest) IsProcessed() bool {
+	sr.mutex.Lock()
+	defer sr.mutex.Unlock()
+
 	return sr.cancel != nil
 }
 
@@ -6,12 +6,27 @@ import (
 	"strings"
 
 	"github.com/timoth-y/chainmetric-core/models"
+	"github.com/timoth-y/chainmetric-core/models/requests"
+
+	"github.com/timoth-y/chainmetric-iot/core/dev/sensor"
 )
 
 type DeviceSpecs struct {
 	Network
 	Supports []models.Metric `json:"supports"`
 	State models.DeviceState `json:"state"`
+	// Capabilities maps measurement capabilities declared by sensors to their IDs.
+	Capabilities map[string]sensor.Capabilities `json:"capabilities,omitempty"`
+	// Quarantined lists IDs of the sensors excluded from readings due to consecutive failures.
+	Quarantined []string `json:"quarantined,omitempty"`
+}
+
+// DeviceSpecsUpdateRequest extends requests.DeviceUpdateRequest with measurement capabilities
+// and health of the device sensors, so that they are published to the blockchain ledger along with the rest of the specs.
+type DeviceSpecsUpdateRequest struct {
+	requests.DeviceUpdateRequest
+	Capabilities map[string]sensor.Capabilities `json:"capabilities,omitempty"`
+	Quarantined  *[]string                      `json:"quarantined,omitempty"`
 }
 
 func (ds DeviceSpecs) Encode() string {
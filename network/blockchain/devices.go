@@ -12,6 +12,7 @@ import (
 	"github.com/timoth-y/chainmetric-core/models"
 	"github.com/timoth-y/chainmetric-core/models/requests"
 
+	"github.com/timoth-y/chainmetric-iot/model"
 	"github.com/timoth-y/chainmetric-iot/shared"
 )
 
@@ -47,6 +48,15 @@ func (dc *DevicesContract) Exists(id string) (bool, error) {
 
 // Update updates device on the blockchain ledger.
 func (dc *DevicesContract) Update(id string, req requests.DeviceUpdateRequest) error {
+	return dc.update(id, req)
+}
+
+// UpdateSpecs updates device specs on the blockchain ledger, including its sensors capabilities.
+func (dc *DevicesContract) UpdateSpecs(id string, req model.DeviceSpecsUpdateRequest) error {
+	return dc.update(id, req)
+}
+
+func (dc *DevicesContract) update(id string, req interface{}) error {
 	payload, err := json.Marshal(req)
 	if err != nil {
 		return err
@@ -2,7 +2,8 @@ package blockchain
 
 import (
 	"github.com/hyperledger/fabric-sdk-go/pkg/gateway"
-	"github.com/timoth-y/chainmetric-core/models"
+
+	"github.com/timoth-y/chainmetric-iot/model"
 )
 
 // ReadingsContract defines access to blockchain Smart Contract for managing metric readings.
@@ -15,8 +16,8 @@ func (rc *ReadingsContract) init() {
 	rc.contract = client.network.GetContract("readings")
 }
 
-// Post sends models.MetricReadings record to blockchain network for processing.
-func (rc *ReadingsContract) Post(readings models.MetricReadings) error {
-	_, err := rc.contract.SubmitTransaction("Post", string(readings.Encode()))
+// Post sends model.MetricReadingsRecord to blockchain network for processing.
+func (rc *ReadingsContract) Post(record model.MetricReadingsRecord) error {
+	_, err := rc.contract.SubmitTransaction("Post", string(record.Encode()))
 	return err
 }
@@ -17,6 +17,7 @@ func initConfig() {
 	viper.SetDefault("device.id_file_path", "../device.id")
 	viper.SetDefault("device.register_timeout_duration", "1m")
 	viper.SetDefault("device.i2c_scan_timeout", "100ms")
+	viper.SetDefault("device.i2c_mux.enabled", true)
 	viper.SetDefault("device.hotswap_detect_interval", "3s")
 	viper.SetDefault("device.local_cache_path", "/var/cache")
 	viper.SetDefault("device.ping_timer_interval", "1m")
@@ -24,6 +25,29 @@ func initConfig() {
 	viper.SetDefault("device.battery_check_interval", "1m")
 
 	viper.SetDefault("engine.sensor_sleep_standby_timeout", "1m")
+	viper.SetDefault("engine.default_read_duration", "1s")
+	viper.SetDefault("engine.min_read_budget", "500ms")
+	viper.SetDefault("engine.read_budget_margin", 1.5)
+	viper.SetDefault("engine.aggregation.default", "median")
+	viper.SetDefault("engine.coalesce_window", "200ms")
+	viper.SetDefault("engine.queue.capacity", 32)
+	viper.SetDefault("engine.queue.full_policy", "merge")
+	viper.SetDefault("engine.queue.max_concurrent_harvests", 4)
+	viper.SetDefault("engine.scheduling.mode", "relative")
+	viper.SetDefault("engine.scheduling.jitter", "0s")
+	viper.SetDefault("engine.scheduling.missed_tick_policy", "skip")
+	viper.SetDefault("engine.health.failure_threshold", 3)
+	viper.SetDefault("engine.health.quarantine_backoff", "30s")
+	viper.SetDefault("engine.health.max_quarantine_backoff", "10m")
+	viper.SetDefault("engine.calibration.file", "")
+	viper.SetDefault("engine.excursion.monitor_interval", "30s")
+	viper.SetDefault("engine.excursion.elevated_interval", "10s")
+	viper.SetDefault("engine.deadband.absolute", 0)
+	viper.SetDefault("engine.deadband.relative", 0)
+	viper.SetDefault("engine.deadband.max_silence", "0s")
+	viper.SetDefault("engine.window.sample_interval", "0s")
+	viper.SetDefault("engine.recording.file", "")
+	viper.SetDefault("engine.warm_up.policy", "provisional")
 
 	viper.SetDefault("blockchain.connection_config", "connection.yaml")
 	viper.SetDefault("blockchain.identity.certificate", "../identity.pem")
@@ -35,6 +59,16 @@ func initConfig() {
 	viper.SetDefault("bluetooth.advertise_duration", "1m")
 
 	viper.SetDefault("sensors.analog.samples_per_read", 100)
+	viper.SetDefault("sensors.analog.spike_filter_window", 0)
+	viper.SetDefault("sensors.virtual.sea_level_pressure", 1013.25)
+	viper.SetDefault("sensors.ccs811.warm_up", "20m")
+	viper.SetDefault("sensors.mq9.preheat", "2m")
+	viper.SetDefault("sensors.adxl345.burst.rate", 0)
+	viper.SetDefault("sensors.adxl345.burst.samples", 512)
+	viper.SetDefault("sensors.replay.file", "")
+	viper.SetDefault("sensors.replay.speed", 1)
+	viper.SetDefault("sensors.static", []interface{}{})
+	viper.SetDefault("sensors.aliases", map[string]string{})
 
 	viper.SetDefault("display.enabled", true)
 	viper.SetDefault("display.width", 240)